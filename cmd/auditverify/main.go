@@ -0,0 +1,66 @@
+// auditverify 校验 logger.AuditLogger 写入的审计日志，并报告第一处断开的链接。
+//
+// 用法：
+//
+//	auditverify [-key KEY | -key-file FILE] audit.log...
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/favbox/pkg/logger"
+)
+
+func main() {
+	key := flag.String("key", "", "HMAC key")
+	keyFile := flag.String("key-file", "", "file containing the HMAC key, trailing newline is ignored")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-key KEY | -key-file FILE] audit.log...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	secret := []byte(*key)
+	if *keyFile != "" {
+		data, err := os.ReadFile(*keyFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		secret = bytes.TrimRight(data, "\r\n")
+	}
+
+	if !verify(flag.Args(), secret, os.Stdout, os.Stderr) {
+		os.Exit(1)
+	}
+}
+
+// verify 依次校验 paths，结果写入 stdout，读取失败写入 stderr，全部通过时返回 true。
+func verify(paths []string, secret []byte, stdout, stderr io.Writer) bool {
+	ok := true
+	for _, path := range paths {
+		result, err := logger.VerifyAuditFile(path, secret)
+		var auditErr *logger.AuditError
+		switch {
+		case errors.As(err, &auditErr):
+			fmt.Fprintf(stdout, "%s: %v\n", path, auditErr)
+			ok = false
+		case err != nil:
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			ok = false
+		default:
+			fmt.Fprintf(stdout, "%s: ok, %d entries, last hash %s\n", path, result.Entries, result.LastHash)
+		}
+	}
+	return ok
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/favbox/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = []byte("secret")
+
+// writeAudit 写入 n 条审计记录，返回文件路径和内容。
+func writeAudit(t *testing.T, n int) (string, []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	a := logger.NewAuditLogger(&buf, logger.WithAuditKey(testKey))
+	for i := 0; i < n; i++ {
+		assert.NoError(t, a.Log("login", logger.F("user", "alice"), logger.F("n", i)))
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path, buf.Bytes()
+}
+
+func TestVerify(t *testing.T) {
+	path, _ := writeAudit(t, 3)
+
+	var stdout, stderr bytes.Buffer
+	assert.True(t, verify([]string{path}, testKey, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), path+": ok, 3 entries, last hash ")
+	assert.Empty(t, stderr.String())
+
+	stdout.Reset()
+	assert.False(t, verify([]string{path}, []byte("wrong"), &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "line 1 (seq 1): hash mismatch")
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	path, data := writeAudit(t, 3)
+	lines := strings.SplitAfter(string(data), "\n")
+	lines[1] = strings.Replace(lines[1], "alice", "mallory", 1)
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "")), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	assert.False(t, verify([]string{path}, testKey, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "line 2 (seq 2): hash mismatch")
+}
+
+func TestVerify_Truncated(t *testing.T) {
+	path, data := writeAudit(t, 3)
+
+	// 最后一条记录被截断
+	assert.NoError(t, os.WriteFile(path, data[:len(data)-10], 0o644))
+	var stdout, stderr bytes.Buffer
+	assert.False(t, verify([]string{path}, testKey, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "line 3 (seq 3): missing hash")
+
+	// 开头的记录被删除
+	lines := strings.SplitAfter(string(data), "\n")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines[1:], "")), 0o644))
+	stdout.Reset()
+	assert.False(t, verify([]string{path}, testKey, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "line 1 (seq 1): sequence 2, want 1")
+}
+
+func TestVerify_Missing(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.False(t, verify([]string{filepath.Join(t.TempDir(), "missing.log")}, testKey, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "missing.log")
+}
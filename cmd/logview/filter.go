@@ -0,0 +1,177 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+)
+
+// filter 决定哪些日志条目需要输出。
+type filter struct {
+	minLevel *logger.Level
+	since    time.Time
+	until    time.Time
+	modules  map[string]bool
+	exprs    []*expr
+}
+
+// active 判断是否设置了任何条件。
+func (f *filter) active() bool {
+	return f.minLevel != nil || !f.since.IsZero() || !f.until.IsZero() || len(f.modules) > 0 || len(f.exprs) > 0
+}
+
+// match 判断条目 r 是否满足全部条件。缺少级别或时间的条目不满足对应的条件。
+func (f *filter) match(r *record) bool {
+	if f.minLevel != nil && (!r.HasLevel || r.Level < *f.minLevel) {
+		return false
+	}
+	if !f.since.IsZero() && (!r.HasTime || r.Time.Before(f.since)) {
+		return false
+	}
+	if !f.until.IsZero() && (!r.HasTime || r.Time.After(f.until)) {
+		return false
+	}
+	if len(f.modules) > 0 && !f.modules[r.Module] {
+		return false
+	}
+	for _, e := range f.exprs {
+		if !e.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// expr 是一个字段条件，支持：
+//
+//	key         字段存在
+//	!key        字段不存在
+//	key=value   等于
+//	key!=value  不等于
+//	key~regexp  匹配正则表达式
+//	key>n key>=n key<n key<=n  比较，两边都是数字时按数值比较，都是时长（如 "250ms"、"1s"）时按时长比较，
+//	                           否则按字符串比较
+type expr struct {
+	key   string
+	op    string
+	value string
+	num   float64
+	isNum bool
+	dur   time.Duration
+	isDur bool
+	re    *regexp.Regexp
+}
+
+var exprOps = []string{"!=", ">=", "<=", "=", "~", ">", "<"}
+
+// parseExpr 解析字段条件。
+func parseExpr(s string) (*expr, error) {
+	if strings.HasPrefix(s, "!") && !strings.ContainsAny(s[1:], "!=<>~") {
+		if s[1:] == "" {
+			return nil, fmt.Errorf("invalid expression %q: missing key", s)
+		}
+		return &expr{key: s[1:], op: "!"}, nil
+	}
+
+	i := strings.IndexAny(s, "!=<>~")
+	if i < 0 {
+		return &expr{key: s, op: ""}, nil
+	}
+	if i == 0 {
+		return nil, fmt.Errorf("invalid expression %q: missing key", s)
+	}
+
+	e := &expr{key: s[:i]}
+	for _, op := range exprOps {
+		if strings.HasPrefix(s[i:], op) {
+			e.op = op
+			e.value = s[i+len(op):]
+			break
+		}
+	}
+	switch e.op {
+	case "":
+		return nil, fmt.Errorf("invalid expression %q: unknown operator", s)
+	case "~":
+		re, err := regexp.Compile(e.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", s, err)
+		}
+		e.re = re
+	}
+	if n, err := strconv.ParseFloat(e.value, 64); err == nil {
+		e.num, e.isNum = n, true
+	}
+	if d, err := time.ParseDuration(e.value); err == nil {
+		e.dur, e.isDur = d, true
+	}
+	return e, nil
+}
+
+func (e *expr) match(r *record) bool {
+	v, ok := r.lookup(e.key)
+	switch e.op {
+	case "":
+		return ok
+	case "!":
+		return !ok
+	}
+	if !ok {
+		return e.op == "!="
+	}
+
+	switch e.op {
+	case "=":
+		return v == e.value
+	case "!=":
+		return v != e.value
+	case "~":
+		return e.re.MatchString(v)
+	}
+
+	var order int
+	if n, err := strconv.ParseFloat(v, 64); err == nil && e.isNum {
+		order = cmp.Compare(n, e.num)
+	} else if d, err := time.ParseDuration(v); err == nil && e.isDur {
+		order = cmp.Compare(d, e.dur)
+	} else {
+		order = strings.Compare(v, e.value)
+	}
+	switch e.op {
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "<":
+		return order < 0
+	default:
+		return order <= 0
+	}
+}
+
+// timeLayouts 是 -since 和 -until 接受的时间格式，没有时区的格式按本地时间解析。
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime 解析时间，也接受相对于 now 的时长，例如 "15m" 表示 15 分钟前。
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339, \"2006-01-02 15:04:05\" or a duration", s)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// tailer 逐行读取文件，跟踪模式下在文件被截断或轮转后从头读取新文件。
+type tailer struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial []byte
+	// ended 是轮转前的旧文件末尾没有换行符的最后一行，切换文件后由 next 先返回。
+	ended []byte
+}
+
+func openTailer(path string) (*tailer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &tailer{path: path, file: file, reader: bufio.NewReader(file)}, nil
+}
+
+// next 返回下一整行（不含换行符），读到文件末尾时返回 io.EOF，未结束的行留到下次读取。
+func (t *tailer) next() ([]byte, error) {
+	if t.ended != nil {
+		line := t.ended
+		t.ended = nil
+		return line, nil
+	}
+	line, err := t.reader.ReadBytes('\n')
+	t.offset += int64(len(line))
+	if err != nil {
+		t.partial = append(t.partial, line...)
+		return nil, err
+	}
+	if len(t.partial) > 0 {
+		line = append(t.partial, line...)
+		t.partial = nil
+	}
+	return bytes.TrimRight(line, "\r\n"), nil
+}
+
+// rest 返回文件末尾没有换行符的最后一行。
+func (t *tailer) rest() []byte {
+	line := t.partial
+	t.partial = nil
+	return line
+}
+
+// wait 等待文件出现新内容。文件被截断时从头读取，路径指向新文件时先读完旧文件再切换到新文件。
+func (t *tailer) wait(ctx context.Context, poll time.Duration) error {
+	timer := time.NewTimer(poll)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	fi, err := os.Stat(t.path)
+	if err != nil {
+		// 轮转过程中路径可能暂时不存在，继续读取旧文件。
+		return nil
+	}
+	cur, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !os.SameFile(fi, cur):
+		// 旧文件在轮转前还写入了内容，先读完再切换。
+		if cur.Size() > t.offset {
+			return nil
+		}
+		file, err := os.Open(t.path)
+		if err != nil {
+			return nil
+		}
+		_ = t.file.Close()
+		t.file = file
+		if len(t.partial) > 0 {
+			t.ended = bytes.TrimRight(t.rest(), "\r\n")
+		}
+	case fi.Size() < t.offset:
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	t.reader.Reset(t.file)
+	t.offset = 0
+	t.partial = nil
+	return nil
+}
+
+func (t *tailer) Close() error {
+	return t.file.Close()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/favbox/pkg/logger"
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorBold    = "\x1b[1m"
+	colorDim     = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGray    = "\x1b[90m"
+)
+
+var levelColors = map[logger.Level]string{
+	logger.LevelTrace:  colorGray,
+	logger.LevelDebug:  colorGray,
+	logger.LevelInfo:   colorGreen,
+	logger.LevelNotice: colorCyan,
+	logger.LevelWarn:   colorYellow,
+	logger.LevelError:  colorRed,
+	logger.LevelFatal:  colorBold + colorMagenta,
+}
+
+// printer 将条目格式化为易读的一行，例如：
+//
+//	2006-01-02 15:04:05.000000 INFO   [payment] main.go:12: message key=value
+type printer struct {
+	color      bool
+	timeFormat string
+}
+
+func (p *printer) format(buf *bytes.Buffer, r *record) {
+	if r.HasTime {
+		p.write(buf, colorDim, r.Time.Local().Format(p.timeFormat))
+		buf.WriteByte(' ')
+	}
+	if r.HasLevel {
+		name := strings.ToUpper(r.Level.String())
+		p.write(buf, levelColors[r.Level], name+strings.Repeat(" ", 6-len(name)))
+		buf.WriteByte(' ')
+	}
+	if r.Module != "" {
+		p.write(buf, colorCyan, "["+escape(r.Module)+"]")
+		buf.WriteByte(' ')
+	}
+	if r.Caller != "" {
+		p.write(buf, colorDim, escape(r.Caller)+":")
+		buf.WriteByte(' ')
+	}
+	if r.HasLevel && r.Level >= logger.LevelError {
+		p.write(buf, colorBold, escape(r.Message))
+	} else {
+		buf.WriteString(escape(r.Message))
+	}
+	for _, f := range r.Fields {
+		buf.WriteByte(' ')
+		p.write(buf, colorDim, escape(f.Key)+"=")
+		buf.WriteString(quote(rawString(f.Value)))
+	}
+	buf.WriteByte('\n')
+}
+
+func (p *printer) write(buf *bytes.Buffer, color, s string) {
+	if !p.color || color == "" {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(colorReset)
+}
+
+// quote 为包含空白、引号或控制字符的值加上引号，并转义其中的引号和控制字符。
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \"=") && !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		if c == '"' {
+			b.WriteString(`\"`)
+		} else {
+			writeEscaped(&b, c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// escape 转义 s 中的控制字符，避免日志内容中的 ESC、\r 等字符控制终端。
+func escape(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	var b strings.Builder
+	for _, c := range s {
+		writeEscaped(&b, c)
+	}
+	return b.String()
+}
+
+func writeEscaped(b *strings.Builder, c rune) {
+	switch {
+	case c == '\n':
+		b.WriteString(`\n`)
+	case c == '\r':
+		b.WriteString(`\r`)
+	case c == '\t':
+		b.WriteString(`\t`)
+	case c < utf8.RuneSelf && unicode.IsControl(c):
+		fmt.Fprintf(b, `\x%02x`, c)
+	case unicode.IsControl(c):
+		fmt.Fprintf(b, `\u%04x`, c)
+	default:
+		b.WriteRune(c)
+	}
+}
@@ -0,0 +1,305 @@
+// logview 读取 logger.JSONEncoder 输出的日志文件或标准输入，按条件过滤并以易读的彩色格式输出。
+//
+// 用法：
+//
+//	logview [-level LEVEL] [-since TIME] [-until TIME] [-module NAME,...] [-where EXPR]... [-f] [-n N] [file...]
+//
+// 例如，跟踪 payment 模块最近 10 分钟内耗时超过 1 秒的警告：
+//
+//	logview -f -level warn -module payment -since 10m -where 'duration>1s' app.log
+//
+// 没有设置过滤条件时，无法解析为 JSON 的行原样输出，否则被忽略。输出中的控制字符会被转义，避免改变终端状态。
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+)
+
+func main() {
+	var (
+		f       filter
+		opts    options
+		level   = flag.String("level", "", "minimum level: trace, debug, info, notice, warn, error or fatal")
+		since   = flag.String("since", "", "show entries at or after this time (RFC 3339, \"2006-01-02 15:04:05\" or a duration such as 1h)")
+		until   = flag.String("until", "", "show entries at or before this time, same formats as -since")
+		modules = flag.String("module", "", "comma separated list of modules to show")
+		color   = flag.String("color", "auto", "colorize output: auto, always or never")
+	)
+	flag.Func("where", "field expression: key, !key, key=v, key!=v, key~regexp, key>n, key>=n, key<n, key<=n (repeatable)", func(s string) error {
+		e, err := parseExpr(s)
+		if err != nil {
+			return err
+		}
+		f.exprs = append(f.exprs, e)
+		return nil
+	})
+	flag.BoolVar(&opts.follow, "f", false, "follow files as they grow, across truncation and rotation")
+	flag.IntVar(&opts.last, "n", 0, "only show the last N matching entries already in each file, 0 shows all")
+	flag.BoolVar(&opts.raw, "raw", false, "print matching lines as the original JSON")
+	flag.StringVar(&opts.timeFormat, "time-format", "2006-01-02 15:04:05.000000", "layout used to print timestamps")
+	flag.DurationVar(&opts.poll, "poll", 250*time.Millisecond, "how often to check followed files for changes")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [file...]\n\nReads stdin when no file is given.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	now := time.Now()
+	var err error
+	if *level != "" {
+		lv, perr := logger.ParseLevel(*level)
+		err = errors.Join(err, perr)
+		f.minLevel = &lv
+	}
+	if *since != "" {
+		var perr error
+		f.since, perr = parseTime(*since, now)
+		err = errors.Join(err, perr)
+	}
+	if *until != "" {
+		var perr error
+		f.until, perr = parseTime(*until, now)
+		err = errors.Join(err, perr)
+	}
+	if *modules != "" {
+		f.modules = map[string]bool{}
+		for _, m := range strings.Split(*modules, ",") {
+			f.modules[strings.TrimSpace(m)] = true
+		}
+	}
+	switch *color {
+	case "always":
+		opts.color = true
+	case "never":
+	case "auto":
+		opts.color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	default:
+		err = errors.Join(err, fmt.Errorf("invalid -color %q, expected auto, always or never", *color))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	opts.filter = &f
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := view(ctx, opts, flag.Args(), os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// options 是 view 的配置。
+type options struct {
+	filter     *filter
+	follow     bool
+	last       int
+	raw        bool
+	color      bool
+	timeFormat string
+	poll       time.Duration
+}
+
+// viewer 过滤并输出日志行，可被多个跟踪中的文件并发使用。
+type viewer struct {
+	opts    options
+	printer printer
+
+	mu  sync.Mutex
+	out *bufio.Writer
+}
+
+// view 按 opts 输出 paths 中的日志，paths 为空或为 "-" 时读取 stdin。
+func view(ctx context.Context, opts options, paths []string, stdin io.Reader, stdout io.Writer) error {
+	if opts.filter == nil {
+		opts.filter = &filter{}
+	}
+	if opts.poll <= 0 {
+		opts.poll = 250 * time.Millisecond
+	}
+	v := &viewer{
+		opts:    opts,
+		printer: printer{color: opts.color, timeFormat: opts.timeFormat},
+		out:     bufio.NewWriter(stdout),
+	}
+	if v.printer.timeFormat == "" {
+		v.printer.timeFormat = time.RFC3339Nano
+	}
+	defer v.flush()
+
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	var tailers []*tailer
+	defer func() {
+		for _, t := range tailers {
+			_ = t.Close()
+		}
+	}()
+	for _, path := range paths {
+		if path == "-" {
+			if err := v.readAll(stdin); err != nil {
+				return err
+			}
+			continue
+		}
+		t, err := openTailer(path)
+		if err != nil {
+			return err
+		}
+		tailers = append(tailers, t)
+		if err := v.readExisting(t); err != nil {
+			return err
+		}
+	}
+	v.flush()
+
+	if !opts.follow || len(tailers) == 0 {
+		return nil
+	}
+
+	errs := make(chan error, len(tailers))
+	for _, t := range tailers {
+		go func(t *tailer) {
+			errs <- v.follow(ctx, t)
+		}(t)
+	}
+	var err error
+	for range tailers {
+		err = errors.Join(err, <-errs)
+	}
+	return err
+}
+
+// readAll 输出 r 的全部内容，暂无更多输入时立即刷新，便于通过管道实时查看。
+func (v *viewer) readAll(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			v.emit(bytes.TrimRight(line, "\r\n"))
+		}
+		if br.Buffered() == 0 {
+			v.flush()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readExisting 输出文件现有的内容，设置了 -n 时只输出最后 N 条匹配的条目。
+func (v *viewer) readExisting(t *tailer) error {
+	var ring [][]byte
+	for {
+		line, err := t.next()
+		if err == io.EOF {
+			if !v.opts.follow {
+				if rest := t.rest(); len(rest) > 0 {
+					ring = v.collect(ring, rest)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		ring = v.collect(ring, line)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, out := range ring {
+		_, _ = v.out.Write(out)
+	}
+	return nil
+}
+
+// collect 格式化 line，并在设置了 -n 时只保留最后 N 条。
+func (v *viewer) collect(ring [][]byte, line []byte) [][]byte {
+	out := v.render(line)
+	if out == nil {
+		return ring
+	}
+	ring = append(ring, out)
+	if v.opts.last > 0 && len(ring) > v.opts.last {
+		ring = ring[len(ring)-v.opts.last:]
+	}
+	return ring
+}
+
+// follow 持续输出文件新增的内容，直到 ctx 结束。
+func (v *viewer) follow(ctx context.Context, t *tailer) error {
+	for {
+		line, err := t.next()
+		switch {
+		case err == nil:
+			v.emit(line)
+		case err == io.EOF:
+			v.flush()
+			if err := t.wait(ctx, v.opts.poll); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+}
+
+func (v *viewer) emit(line []byte) {
+	if out := v.render(line); out != nil {
+		v.mu.Lock()
+		_, _ = v.out.Write(out)
+		v.mu.Unlock()
+	}
+}
+
+func (v *viewer) flush() {
+	v.mu.Lock()
+	_ = v.out.Flush()
+	v.mu.Unlock()
+}
+
+// render 返回一行日志的输出内容，不需要输出时返回 nil。
+func (v *viewer) render(line []byte) []byte {
+	r, err := parseRecord(line)
+	if err != nil {
+		if v.opts.filter.active() || len(bytes.TrimSpace(line)) == 0 {
+			return nil
+		}
+		return append([]byte(escape(string(line))), '\n')
+	}
+	if !v.opts.filter.match(r) {
+		return nil
+	}
+	var buf bytes.Buffer
+	if v.opts.raw {
+		buf.Write(r.Raw)
+		buf.WriteByte('\n')
+	} else {
+		v.printer.format(&buf, r)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeLog(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	assert.NoError(t, err)
+	defer f.Close()
+	for _, line := range lines {
+		_, err := f.WriteString(line + "\n")
+		assert.NoError(t, err)
+	}
+}
+
+const (
+	infoLine  = `{"time":"2024-05-01T10:00:00.000000Z","level":"info","module":"payment","caller":"pay.go:10","msg":"charged","amount":120,"user":"alice"}`
+	warnLine  = `{"time":"2024-05-01T10:05:00.000000Z","level":"warn","module":"db","msg":"slow query","duration":1.5}`
+	errorLine = `{"time":"2024-05-01T10:10:00.000000Z","level":"error","module":"payment","msg":"declined","user":"bob","reason":"card expired"}`
+)
+
+func TestParseRecord(t *testing.T) {
+	r, err := parseRecord([]byte(infoLine))
+	assert.NoError(t, err)
+	assert.True(t, r.HasTime)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), r.Time.UTC())
+	assert.Equal(t, logger.LevelInfo, r.Level)
+	assert.Equal(t, "payment", r.Module)
+	assert.Equal(t, "pay.go:10", r.Caller)
+	assert.Equal(t, "charged", r.Message)
+	assert.Len(t, r.Fields, 2)
+	assert.Equal(t, "amount", r.Fields[0].Key)
+	assert.Equal(t, "user", r.Fields[1].Key)
+
+	_, err = parseRecord([]byte("plain text"))
+	assert.Error(t, err)
+}
+
+func TestExpr(t *testing.T) {
+	r, err := parseRecord([]byte(errorLine))
+	assert.NoError(t, err)
+
+	cases := map[string]bool{
+		"user":           true,
+		"!user":          false,
+		"!amount":        true,
+		"user=bob":       true,
+		"user!=bob":      false,
+		"amount!=1":      true,
+		"reason~exp":     true,
+		"reason~^exp":    false,
+		"msg=declined":   true,
+		"level=error":    true,
+		"user>alice":     true,
+		"user<=alice":    false,
+		"module~pay|db":  true,
+		"amount>1":       false,
+		"reason=card":    false,
+		"time>=2024-05-": true,
+	}
+	for s, want := range cases {
+		e, err := parseExpr(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, e.match(r), s)
+	}
+
+	w, _ := parseRecord([]byte(warnLine))
+	e, _ := parseExpr("duration>1")
+	assert.True(t, e.match(w))
+	e, _ = parseExpr("duration>=10")
+	assert.False(t, e.match(w))
+
+	// JSONEncoder 将 time.Duration 编码为 "250ms" 这样的字符串。
+	d, _ := parseRecord([]byte(`{"level":"info","msg":"query","duration":"250ms"}`))
+	for s, want := range map[string]bool{
+		"duration>1s":     false,
+		"duration<1s":     true,
+		"duration>100ms":  true,
+		"duration>=250ms": true,
+		"duration<=0.25s": true,
+		"duration>1m":     false,
+		"duration>1":      true, // 没有单位的数字不是时长，按字符串比较
+	} {
+		e, err := parseExpr(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, e.match(d), s)
+	}
+
+	for _, s := range []string{"=x", "!", "a~(", "a!b"} {
+		_, err := parseExpr(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tm, err := parseTime("90m", now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-90*time.Minute), tm)
+
+	tm, err = parseTime("2024-05-01T10:00:00Z", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), tm.UTC())
+
+	_, err = parseTime("yesterday", now)
+	assert.Error(t, err)
+}
+
+func TestView(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLog(t, path, infoLine, "not json", warnLine, errorLine)
+
+	lv := logger.LevelInfo
+	since, _ := parseTime("2024-05-01T10:01:00Z", time.Now())
+	user, _ := parseExpr("user")
+	var out bytes.Buffer
+	err := view(context.Background(), options{
+		filter: &filter{
+			minLevel: &lv,
+			since:    since,
+			modules:  map[string]bool{"payment": true},
+			exprs:    []*expr{user},
+		},
+		timeFormat: "15:04:05",
+	}, []string{path}, nil, &out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "%s ERROR  [payment] declined user=bob reason=\"card expired\"\n",
+		strings.Replace(out.String(), time.Date(2024, 5, 1, 10, 10, 0, 0, time.UTC).Local().Format("15:04:05"), "%s", 1))
+}
+
+func TestView_Unfiltered(t *testing.T) {
+	var out bytes.Buffer
+	err := view(context.Background(), options{raw: true, last: 2}, nil,
+		strings.NewReader(infoLine+"\nnot json\n"+warnLine), &out)
+	assert.NoError(t, err)
+	// -n 只作用于文件，stdin 输出全部内容。
+	assert.Equal(t, infoLine+"\nnot json\n"+warnLine+"\n", out.String())
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLog(t, path, infoLine, warnLine, errorLine)
+	out.Reset()
+	assert.NoError(t, view(context.Background(), options{raw: true, last: 2}, []string{path}, nil, &out))
+	assert.Equal(t, warnLine+"\n"+errorLine+"\n", out.String())
+}
+
+func TestView_Color(t *testing.T) {
+	var out bytes.Buffer
+	err := view(context.Background(), options{color: true}, nil, strings.NewReader(errorLine), &out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), colorRed+"ERROR "+colorReset)
+	assert.Contains(t, out.String(), colorBold+"declined"+colorReset)
+}
+
+func TestView_EscapesControl(t *testing.T) {
+	line := `{"level":"info","module":"m\u001b[2J","caller":"a.go:1\r","msg":"hi\u001b]0;x\u0007\rfake","k\u001bey":"v\u009b1"}`
+	var out bytes.Buffer
+	err := view(context.Background(), options{}, nil, strings.NewReader(line+"\nplain \x1b[31mred\n"), &out)
+	assert.NoError(t, err)
+	assert.NotContains(t, out.String(), "\x1b")
+	assert.NotContains(t, out.String(), "\r")
+	assert.NotContains(t, out.String(), "\u009b")
+	assert.Equal(t, "INFO   [m\\x1b[2J] a.go:1\\r: hi\\x1b]0;x\\x07\\rfake k\\x1bey=\"v\\u009b1\"\n"+
+		"plain \\x1b[31mred\n", out.String())
+}
+
+// syncBuffer 是并发安全的 bytes.Buffer。
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestView_Follow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLog(t, path, infoLine)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- view(ctx, options{follow: true, raw: true, poll: 10 * time.Millisecond}, []string{path}, nil, &out)
+	}()
+
+	waitFor := func(want string) {
+		t.Helper()
+		assert.Eventually(t, func() bool { return out.String() == want }, 2*time.Second, 10*time.Millisecond, out.String())
+	}
+	waitFor(infoLine + "\n")
+
+	// 追加的内容，包括分两次写入的行。
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	assert.NoError(t, err)
+	_, _ = f.WriteString(warnLine[:20])
+	time.Sleep(30 * time.Millisecond)
+	_, _ = f.WriteString(warnLine[20:] + "\n")
+	_ = f.Close()
+	waitFor(infoLine + "\n" + warnLine + "\n")
+
+	// 轮转：旧文件被改名，新文件在原路径创建。
+	assert.NoError(t, os.Rename(path, path+".1"))
+	writeLog(t, path, errorLine)
+	waitFor(infoLine + "\n" + warnLine + "\n" + errorLine + "\n")
+
+	// 截断后从头读取。与 tail -f 相同，依据文件变小判断截断。
+	short := `{"msg":"truncated"}`
+	assert.NoError(t, os.WriteFile(path, []byte(short+"\n"), 0o644))
+	waitFor(infoLine + "\n" + warnLine + "\n" + errorLine + "\n" + short + "\n")
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestTailer_RotateDrainsOldFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLog(t, path, infoLine)
+
+	tl, err := openTailer(path)
+	assert.NoError(t, err)
+	defer tl.Close()
+	line, err := tl.next()
+	assert.NoError(t, err)
+	assert.Equal(t, infoLine, string(line))
+	_, err = tl.next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	// 轮转前旧文件又写入了一整行和一个没有换行符的行。
+	writeLog(t, path, warnLine)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	assert.NoError(t, err)
+	_, _ = f.WriteString("tail")
+	_ = f.Close()
+	assert.NoError(t, os.Rename(path, path+".1"))
+	writeLog(t, path, errorLine)
+
+	var lines []string
+	for i := 0; len(lines) < 3 && i < 100; i++ {
+		line, err := tl.next()
+		if err == io.EOF {
+			assert.NoError(t, tl.wait(context.Background(), time.Millisecond))
+			continue
+		}
+		assert.NoError(t, err)
+		lines = append(lines, string(line))
+	}
+	assert.Equal(t, []string{warnLine, "tail", errorLine}, lines)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+)
+
+// field 是日志条目中的一个键值对，Value 为原始 JSON。
+type field struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// record 是解析后的一行 JSON 日志。
+type record struct {
+	Raw      []byte
+	Time     time.Time
+	HasTime  bool
+	Level    logger.Level
+	HasLevel bool
+	Module   string
+	Caller   string
+	Message  string
+	// Fields 是保留键之外的字段，保持原有顺序。
+	Fields []field
+	values map[string]json.RawMessage
+}
+
+var errNotObject = errors.New("not a JSON object")
+
+// parseRecord 解析 logger.JSONEncoder 输出的一行日志，保留字段顺序。
+func parseRecord(line []byte) (*record, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, errNotObject
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	r := &record{Raw: line, values: map[string]json.RawMessage{}}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		r.values[key] = raw
+
+		switch key {
+		case logger.JSONTimeKey:
+			if t, err := time.Parse(time.RFC3339Nano, rawString(raw)); err == nil {
+				r.Time, r.HasTime = t, true
+			}
+		case logger.JSONLevelKey:
+			if lv, err := logger.ParseLevel(rawString(raw)); err == nil {
+				r.Level, r.HasLevel = lv, true
+			}
+		case logger.JSONModuleKey:
+			r.Module = rawString(raw)
+		case logger.JSONCallerKey:
+			r.Caller = rawString(raw)
+		case logger.JSONMessageKey:
+			r.Message = rawString(raw)
+		default:
+			r.Fields = append(r.Fields, field{Key: key, Value: raw})
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// lookup 返回键 key 的显示值，包括保留键。
+func (r *record) lookup(key string) (string, bool) {
+	raw, ok := r.values[key]
+	if !ok {
+		return "", false
+	}
+	return rawString(raw), true
+}
+
+// rawString 返回 JSON 值的显示文本：字符串去掉引号，其他值保持紧凑的 JSON。
+func rawString(raw json.RawMessage) string {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditGenesisHash 是审计日志第一条记录的 prev 值。
+var AuditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// ErrAuditFailed 表示审计日志之前的写入失败，文件末尾可能留有不完整的记录，之后的记录不再写入。
+var ErrAuditFailed = errors.New("logger: audit log failed")
+
+// auditHashMarker 分隔记录内容与哈希值，哈希计算覆盖该标记之前的全部字节。
+const auditHashMarker = `,"hash":"`
+
+// AuditLogger 以 JSON 行的格式写入防篡改的审计日志。
+// 每条记录都携带上一条记录的哈希值，修改、删除或重排任意记录都会使哈希链断开。
+// 配置密钥后使用 HMAC-SHA256，否则使用 SHA-256。
+//
+// 注意：哈希链无法发现末尾记录被整体截掉，如有需要应将 LastHash 定期保存到外部系统。
+type AuditLogger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	key    []byte
+	seq    uint64
+	prev   string
+	err    error
+	closer io.Closer
+}
+
+// AuditOption 是审计日志的配置项。
+type AuditOption func(*AuditLogger)
+
+// WithAuditKey 使用 key 计算 HMAC-SHA256，校验时需要提供相同的密钥。
+func WithAuditKey(key []byte) AuditOption {
+	return func(a *AuditLogger) {
+		a.key = key
+	}
+}
+
+// auditRecord 是审计记录中参与哈希计算的部分。
+type auditRecord struct {
+	Seq    uint64         `json:"seq"`
+	Time   string         `json:"time"`
+	Event  string         `json:"event"`
+	Fields map[string]any `json:"fields,omitempty"`
+	Prev   string         `json:"prev"`
+}
+
+// NewAuditLogger 创建写入 w 的审计日志，哈希链从头开始。
+func NewAuditLogger(w io.Writer, opts ...AuditOption) *AuditLogger {
+	a := &AuditLogger{
+		w:    w,
+		prev: AuditGenesisHash,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// OpenAuditFile 以追加模式打开审计日志文件。
+// 已有内容会先被校验，校验失败时返回 *AuditError；校验通过后从最后一条记录继续哈希链。
+func OpenAuditFile(path string, opts ...AuditOption) (*AuditLogger, error) {
+	a := NewAuditLogger(nil, opts...)
+
+	if f, err := os.Open(path); err == nil {
+		result, verr := VerifyAudit(f, a.key)
+		_ = f.Close()
+		if verr != nil {
+			return nil, verr
+		}
+		a.seq, a.prev = result.Entries, result.LastHash
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	a.w, a.closer = f, f
+	return a, nil
+}
+
+// Log 写入一条审计记录。
+// 写入失败后审计日志进入失败状态，此后的调用都返回包装了 ErrAuditFailed 的错误，
+// 以免新记录接在不完整的行之后。
+func (a *AuditLogger) Log(event string, fields ...Field) error {
+	rec := auditRecord{Event: event}
+	if len(fields) > 0 {
+		rec.Fields = make(map[string]any, len(fields))
+		for _, f := range fields {
+			rec.Fields[f.Key] = auditValue(f.Value)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.err != nil {
+		return a.err
+	}
+	// 在锁内取时间，保证记录的时间与序号同序
+	rec.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	rec.Seq = a.seq + 1
+	rec.Prev = a.prev
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	sum := auditSum(a.key, payload)
+
+	var line bytes.Buffer
+	line.Write(payload[:len(payload)-1])
+	line.WriteString(auditHashMarker)
+	line.WriteString(sum)
+	line.WriteString("\"}\n")
+	n, err := a.w.Write(line.Bytes())
+	if err == nil && n < line.Len() {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		a.err = fmt.Errorf("%w: %w", ErrAuditFailed, err)
+		return a.err
+	}
+
+	a.seq, a.prev = rec.Seq, sum
+	return nil
+}
+
+// LastHash 返回最后一条记录的哈希值。
+func (a *AuditLogger) LastHash() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.prev
+}
+
+// Close 关闭 OpenAuditFile 打开的文件。
+func (a *AuditLogger) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// auditValue 将不便于 JSON 编码的字段值转换为字符串。
+func auditValue(v any) any {
+	switch val := v.(type) {
+	case error:
+		return val.Error()
+	case time.Duration:
+		return val.String()
+	default:
+		return v
+	}
+}
+
+func auditSum(key, payload []byte) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AuditResult 是审计日志的校验结果。
+type AuditResult struct {
+	// Entries 是校验通过的记录数。
+	Entries uint64
+	// LastHash 是最后一条校验通过的记录的哈希值。
+	LastHash string
+}
+
+// AuditError 描述审计日志中第一处断开的链接。
+type AuditError struct {
+	Line   int
+	Seq    uint64
+	Reason string
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("audit chain broken at line %d (seq %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// VerifyAudit 逐行校验审计日志，返回校验通过的记录数和最后的哈希值。
+// 发现第一处断开的链接时返回 *AuditError。
+func VerifyAudit(r io.Reader, key []byte) (AuditResult, error) {
+	result := AuditResult{LastHash: AuditGenesisHash}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		expected := result.Entries + 1
+		broken := func(format string, v ...any) (AuditResult, error) {
+			return result, &AuditError{Line: line, Seq: expected, Reason: fmt.Sprintf(format, v...)}
+		}
+
+		idx := bytes.LastIndex(raw, []byte(auditHashMarker))
+		if idx < 0 || !bytes.HasSuffix(raw, []byte("\"}")) {
+			return broken("missing hash")
+		}
+		sum := string(raw[idx+len(auditHashMarker) : len(raw)-2])
+		payload := append(append([]byte{}, raw[:idx]...), '}')
+
+		var rec auditRecord
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return broken("malformed entry: %v", err)
+		}
+		if rec.Seq != expected {
+			return broken("sequence %d, want %d", rec.Seq, expected)
+		}
+		if rec.Prev != result.LastHash {
+			return broken("prev hash does not match previous entry")
+		}
+		if !hmac.Equal([]byte(sum), []byte(auditSum(key, payload))) {
+			return broken("hash mismatch")
+		}
+
+		result.Entries, result.LastHash = rec.Seq, sum
+	}
+	return result, scanner.Err()
+}
+
+// VerifyAuditFile 校验 path 指向的审计日志文件。
+func VerifyAuditFile(path string, key []byte) (AuditResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return AuditResult{}, err
+	}
+	defer f.Close()
+	return VerifyAudit(f, key)
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeAudit(t *testing.T, opts ...AuditOption) []string {
+	var buf bytes.Buffer
+	a := NewAuditLogger(&buf, opts...)
+	assert.NoError(t, a.Log("payment.created", F("order_id", "A100"), F("amount", 1999)))
+	assert.NoError(t, a.Log("payment.captured", F("order_id", "A100")))
+	assert.NoError(t, a.Log("payment.refunded", F("order_id", "A100"), F("reason", errors.New("customer request"))))
+	lines := strings.SplitAfter(buf.String(), "\n")
+	return lines[:len(lines)-1]
+}
+
+func verifyLines(lines []string, key []byte) (AuditResult, error) {
+	return VerifyAudit(strings.NewReader(strings.Join(lines, "")), key)
+}
+
+func TestAuditLogger_Verify(t *testing.T) {
+	lines := writeAudit(t)
+	assert.Len(t, lines, 3)
+
+	result, err := verifyLines(lines, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), result.Entries)
+	assert.Len(t, result.LastHash, 64)
+}
+
+func TestAuditLogger_Tampered(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func([]string) []string
+		line   int
+		reason string
+	}{
+		{
+			name: "修改记录",
+			tamper: func(l []string) []string {
+				l[1] = strings.Replace(l[1], "A100", "B200", 1)
+				return l
+			},
+			line:   2,
+			reason: "hash mismatch",
+		},
+		{
+			name: "删除记录",
+			tamper: func(l []string) []string {
+				return append(l[:1], l[2:]...)
+			},
+			line:   2,
+			reason: "sequence 3, want 2",
+		},
+		{
+			name: "重排记录",
+			tamper: func(l []string) []string {
+				l[1], l[2] = l[2], l[1]
+				return l
+			},
+			line:   2,
+			reason: "sequence 3, want 2",
+		},
+		{
+			name: "缺少哈希",
+			tamper: func(l []string) []string {
+				l[0] = "{}\n"
+				return l
+			},
+			line:   1,
+			reason: "missing hash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifyLines(tt.tamper(writeAudit(t)), nil)
+			var auditErr *AuditError
+			assert.ErrorAs(t, err, &auditErr)
+			assert.Equal(t, tt.line, auditErr.Line)
+			assert.Equal(t, tt.reason, auditErr.Reason)
+		})
+	}
+}
+
+func TestAuditLogger_HMAC(t *testing.T) {
+	key := []byte("secret")
+	lines := writeAudit(t, WithAuditKey(key))
+
+	_, err := verifyLines(lines, key)
+	assert.NoError(t, err)
+
+	_, err = verifyLines(lines, []byte("wrong"))
+	var auditErr *AuditError
+	assert.ErrorAs(t, err, &auditErr)
+	assert.Equal(t, 1, auditErr.Line)
+}
+
+func TestOpenAuditFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	a, err := OpenAuditFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, a.Log("first"))
+	assert.NoError(t, a.Close())
+
+	a, err = OpenAuditFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, a.Log("second"))
+	last := a.LastHash()
+	assert.NoError(t, a.Close())
+
+	result, err := VerifyAuditFile(path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), result.Entries)
+	assert.Equal(t, last, result.LastHash)
+
+	data, _ := os.ReadFile(path)
+	assert.NoError(t, os.WriteFile(path, bytes.Replace(data, []byte("first"), []byte("forged"), 1), 0o600))
+	_, err = OpenAuditFile(path)
+	var auditErr *AuditError
+	assert.ErrorAs(t, err, &auditErr)
+}
+
+// shortWriter 在写满 limit 字节后只写入部分内容。
+type shortWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.limit {
+		p = p[:w.limit-w.buf.Len()]
+	}
+	return w.buf.Write(p)
+}
+
+func TestAuditLogger_WriteFailed(t *testing.T) {
+	var first bytes.Buffer
+	assert.NoError(t, NewAuditLogger(&first).Log("a"))
+
+	w := &shortWriter{limit: first.Len() + 10}
+	a := NewAuditLogger(w)
+	assert.NoError(t, a.Log("a"))
+	hash := a.LastHash()
+
+	err := a.Log("b")
+	assert.ErrorIs(t, err, ErrAuditFailed)
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	assert.ErrorIs(t, a.Log("c"), ErrAuditFailed)
+	assert.Equal(t, hash, a.LastHash())
+	assert.Equal(t, first.Len()+10, w.buf.Len())
+
+	_, err = VerifyAudit(&w.buf, nil)
+	var auditErr *AuditError
+	assert.ErrorAs(t, err, &auditErr)
+	assert.Equal(t, 2, auditErr.Line)
+}
@@ -0,0 +1,739 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/favbox/pkg/object"
+)
+
+// ConfigError 描述配置中的一处错误，Path 为出错的配置路径，如 "outputs[1].rotation.max_size"。
+type ConfigError struct {
+	Path    string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	if e.Path == "" {
+		return "logger config: " + e.Message
+	}
+	return "logger config " + e.Path + ": " + e.Message
+}
+
+// NewLoggerFromCollection 使用集合中 key 指向的配置（"点"表示法，为空时使用整个集合）创建记录器。
+func NewLoggerFromCollection(c *object.Collection, key string) (FullLogger, error) {
+	if key == "" {
+		return NewLoggerFromConfig(c.All())
+	}
+	section, ok := asConfigMap(c.Get(key))
+	if !ok {
+		return nil, &ConfigError{Path: key, Message: "expected an object"}
+	}
+	cfg := object.HashMap(section)
+	return NewLoggerFromConfig(&cfg)
+}
+
+// NewLoggerFromConfig 按配置创建记录器。支持的配置项（均可省略）：
+//
+//	{
+//	  "level": "info",
+//	  "caller": true,
+//	  "module": "payment",
+//	  "encoder": "json" 或 {"type": "text", "sanitize": {"strip_ansi": true, "max_message_size": 4096,
+//	             "max_field_size": 1024, "truncate_marker": "...", "allow_control": false},
+//	             "time_format": "rfc3339milli", "timezone": "UTC",
+//	             "order": ["level", "time", "message", "fields"], "template": "{{.Time}} {{.Message}}"},
+//	  "outputs": ["stderr", "stdout",
+//	              {"type": "file", "path": "/var/log/app.log", "rotation": {...}},
+//	              {"type": "reopen", "path": "/var/log/app.log"},
+//	              {"type": "split", "dir": "/var/log/app", "pattern": "{level}.log",
+//	               "levels": ["info", "warn", "error"], "cumulative": true, "rotation": {...}},
+//	              {"type": "journald", "socket": "/run/systemd/journal/socket"}],
+//	  "rotation": {"max_size": "100MB", "interval": "24h", "max_backups": 7, "max_age": "168h"},
+//	  "sampling": {"tick": "1s", "initial": 100, "thereafter": 10},
+//	  "redact": {"keys": ["password"], "patterns": ["\\d{16}"], "replacement": "***"},
+//	  "modules": {"db": "warn"}
+//	}
+//
+// 顶层的 rotation 是文件类输出的默认轮转配置，journald 输出要求 encoder 为 journal。time_format 可以是 default、rfc3339、rfc3339milli、
+// rfc3339nano、unix、unixmilli、unixmicro、unixnano 或 time 包的布局。配置有误时返回由 *ConfigError 组成的 errors.Join 错误。
+// 返回的记录器实现了 io.Closer，用于关闭打开的文件。
+func NewLoggerFromConfig(cfg *object.HashMap) (FullLogger, error) {
+	if cfg == nil {
+		cfg = &object.HashMap{}
+	}
+	r := &configReader{}
+	m := map[string]any(*cfg)
+	r.checkKeys(m, "", "level", "caller", "module", "encoder", "outputs", "rotation", "sampling", "redact", "modules")
+
+	var opts []Option
+	if lv, ok := r.level(m, "", "level"); ok {
+		opts = append(opts, WithLevel(lv))
+	}
+	if caller, ok := r.boolean(m, "", "caller"); ok {
+		opts = append(opts, WithCaller(caller))
+	}
+	if module, ok := r.str(m, "", "module"); ok {
+		opts = append(opts, WithModule(module))
+	}
+	enc := r.encoder(m["encoder"], "encoder")
+	if enc != nil {
+		opts = append(opts, WithEncoder(enc))
+	}
+	if sampling, ok := r.sampling(m["sampling"], "sampling"); ok {
+		opts = append(opts, WithSampling(sampling))
+	}
+	if redactor := r.redactor(m["redact"], "redact"); redactor != nil {
+		opts = append(opts, WithRedactor(redactor))
+	}
+	if modules, ok := r.object(m, "", "modules"); ok {
+		for _, name := range sortedKeys(modules) {
+			if lv, ok := r.level(modules, "modules", name); ok {
+				opts = append(opts, WithModuleLevel(name, lv))
+			}
+		}
+	}
+
+	var defaultRotate RotateConfig
+	if v, ok := m["rotation"]; ok {
+		defaultRotate = r.rotation(v, "rotation")
+	}
+	openers := r.outputs(m["outputs"], enc, defaultRotate)
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	// 配置全部有效后才打开文件，避免出错时留下空文件。
+	var writers []io.Writer
+	var closers []io.Closer
+	for _, o := range openers {
+		w, c, err := o.open()
+		if err != nil {
+			r.fail(o.path, "%v", err)
+			continue
+		}
+		writers = append(writers, w)
+		if c != nil {
+			closers = append(closers, c)
+		}
+	}
+	if err := r.err(); err != nil {
+		for _, c := range closers {
+			_ = c.Close()
+		}
+		return nil, err
+	}
+
+	switch len(writers) {
+	case 0:
+		opts = append(opts, WithOutput(io.Discard))
+	case 1:
+		opts = append(opts, WithOutput(writers[0]))
+	default:
+		opts = append(opts, WithOutput(multiWriter(writers)))
+	}
+	l := NewLogger(opts...).(*defaultLogger)
+	l.closers = closers
+	return l, nil
+}
+
+// configReader 读取配置值并收集错误，使一次调用能报告全部问题。
+type configReader struct {
+	errs []error
+}
+
+func (r *configReader) fail(path, format string, v ...any) {
+	r.errs = append(r.errs, &ConfigError{Path: path, Message: fmt.Sprintf(format, v...)})
+}
+
+func (r *configReader) err() error {
+	return errors.Join(r.errs...)
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func typeName(v any) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// asConfigMap 将各种形式的对象转换为 map[string]any。
+func asConfigMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case object.HashMap:
+		return m, true
+	case *object.HashMap:
+		if m == nil {
+			return nil, false
+		}
+		return *m, true
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *configReader) checkKeys(m map[string]any, path string, allowed ...string) {
+	for _, k := range sortedKeys(m) {
+		known := false
+		for _, a := range allowed {
+			if k == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			r.fail(joinPath(path, k), "unknown key, expected one of %s", strings.Join(allowed, ", "))
+		}
+	}
+}
+
+func (r *configReader) object(m map[string]any, path, key string) (map[string]any, bool) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil, false
+	}
+	obj, ok := asConfigMap(v)
+	if !ok {
+		r.fail(joinPath(path, key), "expected an object, got %s", typeName(v))
+	}
+	return obj, ok
+}
+
+func (r *configReader) str(m map[string]any, path, key string) (string, bool) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return "", false
+	}
+	s, ok := v.(string)
+	if !ok {
+		r.fail(joinPath(path, key), "expected a string, got %s", typeName(v))
+	}
+	return s, ok
+}
+
+func (r *configReader) requiredStr(m map[string]any, path, key string) (string, bool) {
+	if v, ok := m[key]; !ok || v == nil || v == "" {
+		r.fail(joinPath(path, key), "is required")
+		return "", false
+	}
+	return r.str(m, path, key)
+}
+
+func (r *configReader) boolean(m map[string]any, path, key string) (bool, bool) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return false, false
+	}
+	b, ok := v.(bool)
+	if !ok {
+		r.fail(joinPath(path, key), "expected a boolean, got %s", typeName(v))
+	}
+	return b, ok
+}
+
+func (r *configReader) level(m map[string]any, path, key string) (Level, bool) {
+	s, ok := r.str(m, path, key)
+	if !ok {
+		return 0, false
+	}
+	lv, err := ParseLevel(s)
+	if err != nil {
+		r.fail(joinPath(path, key), "%v, expected one of %s or warning", err, strings.Join(names, ", "))
+		return 0, false
+	}
+	return lv, true
+}
+
+func (r *configReader) levels(m map[string]any, path, key string) []Level {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil
+	}
+	items, ok := v.([]any)
+	if list, isStrings := v.([]string); isStrings {
+		items, ok = make([]any, len(list)), true
+		for i, name := range list {
+			items[i] = name
+		}
+	}
+	if !ok {
+		r.fail(joinPath(path, key), "expected an array of levels, got %s", typeName(v))
+		return nil
+	}
+	var levels []Level
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", joinPath(path, key), i)
+		name, ok := item.(string)
+		if !ok {
+			r.fail(itemPath, "expected a level name, got %s", typeName(item))
+			continue
+		}
+		lv, err := ParseLevel(name)
+		if err != nil {
+			r.fail(itemPath, "%v", err)
+			continue
+		}
+		levels = append(levels, lv)
+	}
+	return levels
+}
+
+func (r *configReader) stringList(m map[string]any, path, key string) []string {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		if s, ok := v.([]string); ok {
+			return s
+		}
+		r.fail(joinPath(path, key), "expected an array of strings, got %s", typeName(v))
+		return nil
+	}
+	list := make([]string, 0, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			r.fail(fmt.Sprintf("%s[%d]", joinPath(path, key), i), "expected a string, got %s", typeName(item))
+			continue
+		}
+		list = append(list, s)
+	}
+	return list
+}
+
+// integer 读取非负整数，接受整数、整数值的浮点数、json.Number 和数字字符串。
+func (r *configReader) integer(m map[string]any, path, key string) (int64, bool) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return 0, false
+	}
+	n, ok := toInt64(v)
+	if !ok || n < 0 {
+		r.fail(joinPath(path, key), "expected a non-negative integer, got %s %v", typeName(v), v)
+		return 0, false
+	}
+	return n, true
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), n <= math.MaxInt64
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	case float64:
+		return int64(n), n == math.Trunc(n) && math.Abs(n) < 1<<63
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case string:
+		i, err := strconv.ParseInt(n, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+}
+
+// size 读取字节数，接受整数或带单位的字符串，如 "100MB"。
+func (r *configReader) size(m map[string]any, path, key string) (int64, bool) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return 0, false
+	}
+	if s, ok := v.(string); ok {
+		upper := strings.ToUpper(strings.TrimSpace(s))
+		for _, u := range sizeUnits {
+			if num, found := strings.CutSuffix(upper, u.suffix); found {
+				if n, err := strconv.ParseFloat(strings.TrimSpace(num), 64); err == nil && n >= 0 {
+					return int64(n * float64(u.factor)), true
+				}
+				break
+			}
+		}
+	}
+	if n, ok := toInt64(v); ok && n >= 0 {
+		return n, true
+	}
+	r.fail(joinPath(path, key), "expected a size such as 10485760 or \"10MB\", got %s %v", typeName(v), v)
+	return 0, false
+}
+
+// duration 读取时长，接受 time.ParseDuration 格式的字符串或表示秒数的数字。
+func (r *configReader) duration(m map[string]any, path, key string) (time.Duration, bool) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return 0, false
+	}
+	if d, ok := v.(time.Duration); ok {
+		return d, true
+	}
+	if s, ok := v.(string); ok {
+		if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+			return d, true
+		}
+	} else if n, ok := toInt64(v); ok && n >= 0 {
+		return time.Duration(n) * time.Second, true
+	}
+	r.fail(joinPath(path, key), "expected a duration such as \"1s\" or a number of seconds, got %s %v", typeName(v), v)
+	return 0, false
+}
+
+func (r *configReader) encoder(v any, path string) Encoder {
+	if v == nil {
+		return nil
+	}
+
+	var typ string
+	var sanitizer Sanitizer
+	var m map[string]any
+	switch val := v.(type) {
+	case string:
+		typ = val
+	default:
+		var ok bool
+		m, ok = asConfigMap(v)
+		if !ok {
+			r.fail(path, "expected \"text\", \"json\", \"journal\" or an object, got %s", typeName(v))
+			return nil
+		}
+		r.checkKeys(m, path, "type", "sanitize", "time_format", "timezone", "order", "template")
+		typ, _ = r.str(m, path, "type")
+		if s, ok := r.object(m, path, "sanitize"); ok {
+			sanitizer = r.sanitizer(s, joinPath(path, "sanitize"))
+		}
+	}
+
+	typ = strings.ToLower(typ)
+	if typ != "" && typ != "text" {
+		for _, key := range []string{"time_format", "timezone", "order", "template"} {
+			if _, ok := m[key]; ok {
+				r.fail(joinPath(path, key), "is only supported by the text encoder")
+			}
+		}
+	}
+
+	switch typ {
+	case "", "text":
+		enc := &TextEncoder{Sanitizer: sanitizer}
+		if m != nil {
+			r.textLayout(enc, m, path)
+		}
+		return enc
+	case "json":
+		return &JSONEncoder{Sanitizer: sanitizer}
+	case "journal":
+		return &JournalEncoder{Sanitizer: sanitizer}
+	default:
+		r.fail(path, "unknown encoder type %q, expected text, json or journal", typ)
+		return nil
+	}
+}
+
+// timeFormats 是配置中 time_format 可用的名称，其他值按 time 包的布局处理。
+var timeFormats = map[string]string{
+	"default":      DefaultTimeFormat,
+	"rfc3339":      time.RFC3339,
+	"rfc3339milli": TimeFormatRFC3339Milli,
+	"rfc3339nano":  time.RFC3339Nano,
+	"unix":         TimeFormatUnix,
+	"unixmilli":    TimeFormatUnixMilli,
+	"unixmicro":    TimeFormatUnixMicro,
+	"unixnano":     TimeFormatUnixNano,
+}
+
+// textLayout 读取文本编码器的时间格式、时区、顺序和模板。
+func (r *configReader) textLayout(enc *TextEncoder, m map[string]any, path string) {
+	if format, ok := r.str(m, path, "time_format"); ok {
+		if named, ok := timeFormats[strings.ToLower(format)]; ok {
+			format = named
+		}
+		enc.TimeFormat = format
+	}
+	if tz, ok := r.str(m, path, "timezone"); ok {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			r.fail(joinPath(path, "timezone"), "%v", err)
+		}
+		enc.Location = loc
+	}
+	if _, ok := m["order"]; ok {
+		enc.Order = r.stringList(m, path, "order")
+		for i, part := range enc.Order {
+			switch part {
+			case LayoutTime, LayoutCaller, LayoutLevel, LayoutModule, LayoutMessage, LayoutFields:
+			default:
+				r.fail(fmt.Sprintf("%s[%d]", joinPath(path, "order"), i),
+					"unknown part %q, expected time, caller, level, module, message or fields", part)
+			}
+		}
+	}
+	if text, ok := r.str(m, path, "template"); ok {
+		tmpl, err := ParseTextTemplate(text)
+		if err != nil {
+			r.fail(joinPath(path, "template"), "%v", err)
+		}
+		enc.Template = tmpl
+	}
+}
+
+func (r *configReader) sanitizer(m map[string]any, path string) Sanitizer {
+	r.checkKeys(m, path, "allow_control", "strip_ansi", "max_message_size", "max_field_size", "truncate_marker")
+	var s Sanitizer
+	s.AllowControl, _ = r.boolean(m, path, "allow_control")
+	s.StripANSI, _ = r.boolean(m, path, "strip_ansi")
+	if n, ok := r.size(m, path, "max_message_size"); ok {
+		s.MaxMessageSize = int(n)
+	}
+	if n, ok := r.size(m, path, "max_field_size"); ok {
+		s.MaxFieldSize = int(n)
+	}
+	s.TruncateMarker, _ = r.str(m, path, "truncate_marker")
+	return s
+}
+
+func (r *configReader) rotation(v any, path string) RotateConfig {
+	var cfg RotateConfig
+	m, ok := asConfigMap(v)
+	if !ok {
+		r.fail(path, "expected an object, got %s", typeName(v))
+		return cfg
+	}
+	r.checkKeys(m, path, "max_size", "interval", "max_backups", "max_age")
+	cfg.MaxSize, _ = r.size(m, path, "max_size")
+	cfg.Interval, _ = r.duration(m, path, "interval")
+	if n, ok := r.integer(m, path, "max_backups"); ok {
+		cfg.MaxBackups = int(n)
+	}
+	cfg.MaxAge, _ = r.duration(m, path, "max_age")
+	return cfg
+}
+
+func (r *configReader) sampling(v any, path string) (SamplingConfig, bool) {
+	var cfg SamplingConfig
+	if v == nil {
+		return cfg, false
+	}
+	m, ok := asConfigMap(v)
+	if !ok {
+		r.fail(path, "expected an object, got %s", typeName(v))
+		return cfg, false
+	}
+	r.checkKeys(m, path, "tick", "initial", "thereafter")
+	cfg.Tick, _ = r.duration(m, path, "tick")
+	if n, ok := r.integer(m, path, "initial"); ok {
+		cfg.Initial = int(n)
+	}
+	if n, ok := r.integer(m, path, "thereafter"); ok {
+		cfg.Thereafter = int(n)
+	}
+	return cfg, true
+}
+
+func (r *configReader) redactor(v any, path string) *Redactor {
+	if v == nil {
+		return nil
+	}
+	m, ok := asConfigMap(v)
+	if !ok {
+		r.fail(path, "expected an object, got %s", typeName(v))
+		return nil
+	}
+	r.checkKeys(m, path, "keys", "patterns", "replacement")
+	keys := r.stringList(m, path, "keys")
+	patterns := r.stringList(m, path, "patterns")
+	replacement, _ := r.str(m, path, "replacement")
+
+	redactor, err := NewRedactor(keys, patterns, replacement)
+	if err != nil {
+		r.fail(joinPath(path, "patterns"), "%v", err)
+		return nil
+	}
+	return redactor
+}
+
+// outputOpener 延迟打开一个已通过校验的输出。
+type outputOpener struct {
+	path string
+	open func() (io.Writer, io.Closer, error)
+}
+
+// outputs 校验全部输出配置，返回各输出的打开函数。
+func (r *configReader) outputs(v any, enc Encoder, defaultRotate RotateConfig) []outputOpener {
+	if v == nil {
+		return []outputOpener{{path: "outputs", open: func() (io.Writer, io.Closer, error) {
+			return os.Stderr, nil, nil
+		}}}
+	}
+	items, ok := v.([]any)
+	if !ok {
+		items = []any{v}
+	}
+	if len(items) == 0 {
+		r.fail("outputs", "at least one output is required")
+		return nil
+	}
+
+	var openers []outputOpener
+	for i, item := range items {
+		path := fmt.Sprintf("outputs[%d]", i)
+		if open := r.output(item, path, enc, defaultRotate); open != nil {
+			openers = append(openers, outputOpener{path: path, open: open})
+		}
+	}
+	return openers
+}
+
+func (r *configReader) output(v any, path string, enc Encoder, defaultRotate RotateConfig) func() (io.Writer, io.Closer, error) {
+	m, ok := asConfigMap(v)
+	if !ok {
+		s, isStr := v.(string)
+		if !isStr {
+			r.fail(path, "expected \"stdout\", \"stderr\" or an object, got %s", typeName(v))
+			return nil
+		}
+		m = map[string]any{"type": s}
+	}
+
+	typ, _ := r.str(m, path, "type")
+	rotate := defaultRotate
+	if rv, ok := m["rotation"]; ok {
+		rotate = r.rotation(rv, joinPath(path, "rotation"))
+	}
+
+	switch typ {
+	case "stdout", "stderr":
+		r.checkKeys(m, path, "type")
+		return func() (io.Writer, io.Closer, error) {
+			if typ == "stdout" {
+				return os.Stdout, nil, nil
+			}
+			return os.Stderr, nil, nil
+		}
+	case "file", "reopen":
+		r.checkKeys(m, path, "type", "path", "rotation")
+		file, ok := r.requiredStr(m, path, "path")
+		if !ok {
+			return nil
+		}
+		return func() (io.Writer, io.Closer, error) {
+			if typ == "reopen" {
+				w, err := NewReopenWriter(file)
+				if err != nil {
+					return nil, nil, err
+				}
+				return w, w, nil
+			}
+			w, err := NewRotateWriter(file, rotate)
+			if err != nil {
+				return nil, nil, err
+			}
+			return w, w, nil
+		}
+	case "split":
+		r.checkKeys(m, path, "type", "dir", "pattern", "levels", "cumulative", "rotation")
+		dir, ok := r.requiredStr(m, path, "dir")
+		if !ok {
+			return nil
+		}
+		cfg := SplitConfig{Dir: dir, Rotate: rotate}
+		cfg.Pattern, _ = r.str(m, path, "pattern")
+		cfg.Levels = r.levels(m, path, "levels")
+		cfg.Cumulative, _ = r.boolean(m, path, "cumulative")
+		return func() (io.Writer, io.Closer, error) {
+			w, err := NewLevelSplitWriter(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			return w, w, nil
+		}
+	case "journald":
+		r.checkKeys(m, path, "type", "socket")
+		if _, ok := enc.(*JournalEncoder); !ok {
+			r.fail(path, "journald output requires the \"journal\" encoder")
+			return nil
+		}
+		socket, _ := r.str(m, path, "socket")
+		return func() (io.Writer, io.Closer, error) {
+			w, err := NewJournalWriter(socket)
+			if err != nil {
+				return nil, nil, err
+			}
+			return w, w, nil
+		}
+	case "":
+		r.fail(joinPath(path, "type"), "is required")
+	default:
+		r.fail(joinPath(path, "type"), "unknown output type %q, expected stdout, stderr, file, reopen, split or journald", typ)
+	}
+	return nil
+}
+
+// multiWriter 将日志写入多个输出，并向实现了 LevelWriter 的输出传递级别。
+type multiWriter []io.Writer
+
+func (mw multiWriter) Write(p []byte) (int, error) {
+	var errs []error
+	for _, w := range mw {
+		if _, err := w.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+func (mw multiWriter) WriteLevel(lv Level, p []byte) (int, error) {
+	var errs []error
+	for _, w := range mw {
+		var err error
+		if lw, ok := w.(LevelWriter); ok {
+			_, err = lw.WriteLevel(lv, p)
+		} else {
+			_, err = w.Write(p)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/favbox/pkg/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLoggerFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	logPath := filepath.Join(dir, "app.log")
+	config := `{
+		"logger": {
+			"level": "debug",
+			"caller": false,
+			"encoder": {"type": "json", "sanitize": {"max_message_size": "1KB"}},
+			"outputs": [{"type": "file", "path": "` + filepath.ToSlash(logPath) + `"}],
+			"rotation": {"max_size": "10MB", "max_backups": 3, "interval": "24h"},
+			"sampling": {"tick": "1m", "initial": 2, "thereafter": 0},
+			"redact": {"keys": ["Password"], "patterns": ["\\d{16}"]},
+			"modules": {"db": "error"}
+		}
+	}`
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0o644))
+
+	settings := &object.HashMap{}
+	assert.NoError(t, object.LoadObjectFromFile(configPath, settings))
+	l, err := NewLoggerFromCollection(object.NewCollection(settings), "logger")
+	assert.NoError(t, err)
+
+	l.Trace("hidden by level")
+	for i := 0; i < 5; i++ {
+		l.Info("sampled")
+	}
+	Named(l, "db").Warn("hidden by module level")
+	ctx := WithFields(context.Background(), F("password", "hunter2"), F("note", "card 4111111111111111"))
+	l.CtxDebugf(ctx, "paid with 4111111111111111")
+	assert.NoError(t, l.(io.Closer).Close())
+
+	var entries []map[string]any
+	for _, line := range readLines(t, logPath) {
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m))
+		entries = append(entries, m)
+	}
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "sampled", entries[0]["msg"])
+	assert.Equal(t, "sampled", entries[1]["msg"])
+	assert.Equal(t, "paid with ***", entries[2]["msg"])
+	assert.Equal(t, "***", entries[2]["password"])
+	assert.Equal(t, "card ***", entries[2]["note"])
+	assert.Nil(t, entries[2]["caller"])
+}
+
+func TestNewLoggerFromConfig_Split(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewLoggerFromConfig(&object.HashMap{
+		"outputs": []any{
+			map[string]any{"type": "split", "dir": dir, "levels": []string{"info", "error"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	l.Info("info")
+	l.Error("error")
+	assert.NoError(t, l.(io.Closer).Close())
+
+	assert.Len(t, readLines(t, filepath.Join(dir, "info.log")), 1)
+	assert.Len(t, readLines(t, filepath.Join(dir, "error.log")), 1)
+}
+
+func TestNewLoggerFromConfig_Errors(t *testing.T) {
+	_, err := NewLoggerFromConfig(&object.HashMap{
+		"level":   "verbose",
+		"colour":  true,
+		"encoder": "xml",
+		"outputs": []any{
+			"stdout",
+			map[string]any{"type": "file"},
+			map[string]any{"type": "file", "path": "x.log", "rotation": map[string]any{"max_size": "huge"}},
+			map[string]any{"type": "kafka"},
+		},
+		"sampling": map[string]any{"initial": -1},
+		"redact":   map[string]any{"patterns": []any{"("}},
+		"modules":  map[string]any{"db": 3},
+	})
+	assert.Error(t, err)
+
+	var paths []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var ce *ConfigError
+		assert.True(t, errors.As(e, &ce))
+		paths = append(paths, ce.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"colour",
+		"level",
+		"encoder",
+		"sampling.initial",
+		"redact.patterns",
+		"modules.db",
+		"outputs[1].path",
+		"outputs[2].rotation.max_size",
+		"outputs[3].type",
+	}, paths)
+	assert.True(t, strings.Contains(err.Error(), `logger config level: unknown level "verbose"`))
+}
+
+func TestNewLoggerFromConfig_JournaldEncoder(t *testing.T) {
+	for _, encoder := range []any{nil, "json", map[string]any{"type": "text"}} {
+		cfg := object.HashMap{"outputs": []any{"stderr", map[string]any{"type": "journald"}}}
+		if encoder != nil {
+			cfg["encoder"] = encoder
+		}
+		_, err := NewLoggerFromConfig(&cfg)
+		var ce *ConfigError
+		if assert.ErrorAs(t, err, &ce, "encoder %v", encoder) {
+			assert.Equal(t, "outputs[1]", ce.Path)
+		}
+	}
+
+	_, err := NewLoggerFromConfig(&object.HashMap{
+		"encoder": "journal",
+		"outputs": []any{map[string]any{"type": "journald", "socket": filepath.Join(t.TempDir(), "missing.sock")}},
+	})
+	var ce *ConfigError
+	assert.ErrorAs(t, err, &ce)
+	assert.NotContains(t, ce.Error(), "encoder")
+}
@@ -0,0 +1,362 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var logger FullLogger = NewLogger(withPackageSkip())
+
+// SetOutput 设置默认记录器的输出。默认为 stderr。
+func SetOutput(w io.Writer) {
+	logger.SetOutput(w)
+}
+
+// SetLevel 设置默认记录器的级别。低于该级别的日志不会输出。
+func SetLevel(lv Level) {
+	logger.SetLevel(lv)
+}
+
+// DefaultLogger 返回包级函数使用的记录器。
+func DefaultLogger() FullLogger {
+	return logger
+}
+
+// SetLogger 设置包级函数使用的记录器。
+// 注意：此方法不是并发安全的，应在程序初始化时调用。
+func SetLogger(v FullLogger) {
+	logger = v
+}
+
+// Module 返回默认记录器下名为 name 的模块记录器。
+func Module(name string) FullLogger {
+	return Named(logger, name)
+}
+
+// Named 返回 l 下名为 module 的模块记录器，它与 l 共享输出、编码器和统计。
+// 模块记录器的 SetLevel 只设置该模块的级别，未设置时沿用 l 的级别。
+// l 不是由 NewLogger 创建时原样返回。
+func Named(l FullLogger, module string) FullLogger {
+	ll, ok := l.(*defaultLogger)
+	if !ok {
+		return l
+	}
+	return &defaultLogger{
+		loggerCore: ll.loggerCore,
+		module:     module,
+		depth:      2 + ll.callerSkip,
+	}
+}
+
+// Option 是 NewLogger 的配置项。
+type Option func(*defaultLogger)
+
+// WithLevel 设置记录器的级别，默认为 LevelTrace。
+func WithLevel(lv Level) Option {
+	return func(l *defaultLogger) {
+		l.level.Store(int32(lv))
+	}
+}
+
+// WithOutput 设置记录器的输出，默认为 stderr。实现了 LevelWriter 的输出会按级别写入。
+func WithOutput(w io.Writer) Option {
+	return func(l *defaultLogger) {
+		l.out = w
+	}
+}
+
+// WithEncoder 设置记录器的编码器，默认为 TextEncoder。
+func WithEncoder(enc Encoder) Option {
+	return func(l *defaultLogger) {
+		l.encoder = enc
+	}
+}
+
+// WithCaller 设置是否记录调用位置，默认记录。
+func WithCaller(enabled bool) Option {
+	return func(l *defaultLogger) {
+		l.caller = enabled
+	}
+}
+
+// WithCallerSkip 在计算调用位置时额外跳过 skip 层调用栈，用于封装记录器的场景。
+func WithCallerSkip(skip int) Option {
+	return func(l *defaultLogger) {
+		l.callerSkip += skip
+		l.depth += skip
+	}
+}
+
+// WithModule 设置记录器的模块名。
+func WithModule(name string) Option {
+	return func(l *defaultLogger) {
+		l.module = name
+	}
+}
+
+// WithMetrics 设置记录器使用的统计，默认为 DefaultMetrics，为 nil 时不统计。
+func WithMetrics(m *Metrics) Option {
+	return func(l *defaultLogger) {
+		l.metrics = m
+	}
+}
+
+// WithModuleLevel 设置模块 module 的级别，优先于记录器的级别。
+func WithModuleLevel(module string, lv Level) Option {
+	return func(l *defaultLogger) {
+		l.moduleLevels.Store(module, lv)
+	}
+}
+
+// withPackageSkip 为包级函数多出的一层调用栈修正调用位置。
+func withPackageSkip() Option {
+	return func(l *defaultLogger) {
+		l.depth++
+	}
+}
+
+// loggerCore 是同一记录器及其模块记录器共享的状态。
+type loggerCore struct {
+	mu           sync.Mutex
+	out          io.Writer
+	level        atomic.Int32
+	moduleLevels sync.Map
+	encoder      Encoder
+	caller       bool
+	callerSkip   int
+	metrics      *Metrics
+	sampler      *sampler
+	redactor     *Redactor
+	closers      []io.Closer
+}
+
+type defaultLogger struct {
+	*loggerCore
+	module string
+	depth  int
+}
+
+// NewLogger 创建一个 FullLogger。
+func NewLogger(opts ...Option) FullLogger {
+	l := &defaultLogger{
+		loggerCore: &loggerCore{
+			out:     os.Stderr,
+			encoder: &TextEncoder{},
+			caller:  true,
+			metrics: DefaultMetrics,
+		},
+		depth: 2,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func (ll *defaultLogger) SetOutput(w io.Writer) {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+	ll.out = w
+}
+
+// Close 关闭由 NewLoggerFromConfig 打开的输出。
+func (ll *defaultLogger) Close() error {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	var errs []error
+	for _, c := range ll.closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	ll.closers = nil
+	return errors.Join(errs...)
+}
+
+func (ll *defaultLogger) SetLevel(lv Level) {
+	if ll.module != "" {
+		ll.moduleLevels.Store(ll.module, lv)
+		return
+	}
+	ll.level.Store(int32(lv))
+}
+
+// enabled 判断级别 lv 的日志是否需要输出。
+func (ll *defaultLogger) enabled(lv Level) bool {
+	if ll.module != "" {
+		if mlv, ok := ll.moduleLevels.Load(ll.module); ok {
+			return lv >= mlv.(Level)
+		}
+	}
+	return lv >= Level(ll.level.Load())
+}
+
+// logf 输出日志，skip 为调用方在标准调用栈之外额外增加的栈帧数。
+func (ll *defaultLogger) logf(ctx context.Context, skip int, lv Level, format *string, v ...any) {
+	if !ll.enabled(lv) {
+		return
+	}
+
+	var msg string
+	if format != nil {
+		msg = fmt.Sprintf(*format, v...)
+	} else {
+		msg = fmt.Sprint(v...)
+	}
+
+	if ll.sampler != nil && !ll.sampler.allow(lv, msg) {
+		ll.metrics.addSampled()
+		return
+	}
+
+	e := Entry{
+		Time:    time.Now(),
+		Level:   lv,
+		Message: msg,
+		Module:  ll.module,
+		Fields:  FieldsFromContext(ctx),
+	}
+	if ll.redactor != nil {
+		e.Message = ll.redactor.Message(e.Message)
+		e.Fields = ll.redactor.Fields(e.Fields)
+	}
+	if ll.caller {
+		if _, file, line, ok := runtime.Caller(ll.depth + skip); ok {
+			e.Caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+		}
+	}
+	ll.write(&e)
+
+	if lv == LevelFatal {
+		os.Exit(1)
+	}
+}
+
+func (ll *defaultLogger) write(e *Entry) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}()
+
+	if err := ll.encoder.Encode(buf, e); err != nil {
+		ll.metrics.addDropped()
+		fmt.Fprintf(os.Stderr, "logger: encode entry: %v\n", err)
+		return
+	}
+
+	var err error
+	ll.mu.Lock()
+	if lw, ok := ll.out.(LevelWriter); ok {
+		_, err = lw.WriteLevel(e.Level, buf.Bytes())
+	} else {
+		_, err = ll.out.Write(buf.Bytes())
+	}
+	ll.mu.Unlock()
+	if err != nil {
+		ll.metrics.addDropped()
+		return
+	}
+	ll.metrics.observe(e.Module, e.Level)
+}
+
+func (ll *defaultLogger) Fatal(v ...any) {
+	ll.logf(context.Background(), 0, LevelFatal, nil, v...)
+}
+
+func (ll *defaultLogger) Error(v ...any) {
+	ll.logf(context.Background(), 0, LevelError, nil, v...)
+}
+
+func (ll *defaultLogger) Warn(v ...any) {
+	ll.logf(context.Background(), 0, LevelWarn, nil, v...)
+}
+
+func (ll *defaultLogger) Notice(v ...any) {
+	ll.logf(context.Background(), 0, LevelNotice, nil, v...)
+}
+
+func (ll *defaultLogger) Info(v ...any) {
+	ll.logf(context.Background(), 0, LevelInfo, nil, v...)
+}
+
+func (ll *defaultLogger) Debug(v ...any) {
+	ll.logf(context.Background(), 0, LevelDebug, nil, v...)
+}
+
+func (ll *defaultLogger) Trace(v ...any) {
+	ll.logf(context.Background(), 0, LevelTrace, nil, v...)
+}
+
+func (ll *defaultLogger) Fatalf(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelFatal, &format, v...)
+}
+
+func (ll *defaultLogger) Errorf(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelError, &format, v...)
+}
+
+func (ll *defaultLogger) Warnf(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelWarn, &format, v...)
+}
+
+func (ll *defaultLogger) Noticef(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelNotice, &format, v...)
+}
+
+func (ll *defaultLogger) Infof(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelInfo, &format, v...)
+}
+
+func (ll *defaultLogger) Debugf(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelDebug, &format, v...)
+}
+
+func (ll *defaultLogger) Tracef(format string, v ...any) {
+	ll.logf(context.Background(), 0, LevelTrace, &format, v...)
+}
+
+func (ll *defaultLogger) CtxFatalf(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelFatal, &format, v...)
+}
+
+func (ll *defaultLogger) CtxErrorf(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelError, &format, v...)
+}
+
+func (ll *defaultLogger) CtxWarnf(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelWarn, &format, v...)
+}
+
+func (ll *defaultLogger) CtxNoticef(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelNotice, &format, v...)
+}
+
+func (ll *defaultLogger) CtxInfof(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelInfo, &format, v...)
+}
+
+func (ll *defaultLogger) CtxDebugf(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelDebug, &format, v...)
+}
+
+func (ll *defaultLogger) CtxTracef(ctx context.Context, format string, v ...any) {
+	ll.logf(ctx, 0, LevelTrace, &format, v...)
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultLogger_Level(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithLevel(LevelWarn))
+
+	l.Info("ignored")
+	l.Warnf("kept %d", 1)
+	l.Error("also kept")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "default_test.go:")
+	assert.Contains(t, lines[0], "[Warn] kept 1")
+	assert.Contains(t, lines[1], "[Error] also kept")
+
+	buf.Reset()
+	l.SetLevel(LevelInfo)
+	l.Info("now visible")
+	assert.Contains(t, buf.String(), "[Info] now visible")
+}
+
+func TestDefaultLogger_TextInjection(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithCaller(false))
+
+	ctx := WithFields(context.Background(), F("user", "bob\n[Error] forged"), F("id", 7))
+	l.CtxInfof(ctx, "login %s", "ok\n[Error] forged")
+
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+	assert.Contains(t, out, `[Info] login ok\n[Error] forged user="bob\n[Error] forged" id=7`)
+}
+
+func TestDefaultLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithEncoder(&JSONEncoder{
+		Sanitizer: Sanitizer{StripANSI: true, MaxMessageSize: 5, MaxFieldSize: 3},
+	}))
+
+	ctx := WithFields(context.Background(), F("name", "\x1b[31mabcdef"), F("count", 12))
+	l.CtxErrorf(ctx, "multi\nline message")
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "error", m[JSONLevelKey])
+	assert.Equal(t, "multi"+DefaultTruncateMarker, m[JSONMessageKey])
+	assert.Equal(t, "abc"+DefaultTruncateMarker, m["name"])
+	assert.Equal(t, float64(12), m["count"])
+	assert.Contains(t, m[JSONCallerKey], "default_test.go:")
+}
+
+func TestJSONEncoder_ReservedFieldKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithEncoder(&JSONEncoder{}), WithCaller(false))
+
+	ctx := WithFields(context.Background(),
+		F("level", "error"), F("msg", "forged"), F("time", "0"), F("caller", "x.go:1"), F("module", "admin"))
+	l.CtxInfof(ctx, "real")
+
+	assert.Equal(t, 1, strings.Count(buf.String(), `"level":`))
+	assert.Equal(t, 1, strings.Count(buf.String(), `"msg":`))
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "info", m[JSONLevelKey])
+	assert.Equal(t, "real", m[JSONMessageKey])
+	assert.Nil(t, m[JSONCallerKey])
+	assert.Nil(t, m[JSONModuleKey])
+	assert.Equal(t, "error", m["fields.level"])
+	assert.Equal(t, "forged", m["fields.msg"])
+	assert.Equal(t, "0", m["fields.time"])
+	assert.Equal(t, "x.go:1", m["fields.caller"])
+	assert.Equal(t, "admin", m["fields.module"])
+}
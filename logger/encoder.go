@@ -0,0 +1,267 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Encoder 将日志条目编码为一行输出。
+type Encoder interface {
+	Encode(buf *bytes.Buffer, e *Entry) error
+}
+
+// TextEncoder 以文本格式输出日志，默认格式为：
+//
+//	2006/01/02 15:04:05.000000 main.go:12: [Info] [module] message key=value
+//
+// 时间格式、时区和各部分的顺序可以通过 TimeFormat、Location 和 Order 调整，
+// 设置 Template 时完全由模板决定输出格式。
+type TextEncoder struct {
+	Sanitizer Sanitizer
+	// TimeFormat 是时间的格式，可以是 time 包的布局或 TimeFormatUnix 等常量，默认为 DefaultTimeFormat。
+	TimeFormat string
+	// Location 是输出时间使用的时区，默认为本地时区。
+	Location *time.Location
+	// Order 是各部分的输出顺序，取值为 LayoutTime 等常量，未列出的部分不输出。默认为 DefaultLayout。
+	Order []string
+	// Template 是自定义的输出模板，数据为 TextTemplateData，可以用 ParseTextTemplate 创建。
+	Template *template.Template
+}
+
+// Encode 实现 Encoder 接口。
+func (enc *TextEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	if enc.Template != nil {
+		return enc.encodeTemplate(buf, e)
+	}
+
+	order := enc.Order
+	if order == nil {
+		order = DefaultLayout
+	}
+	start := buf.Len()
+	for _, part := range order {
+		if part == LayoutFields {
+			enc.writeFields(buf, e.Fields)
+			continue
+		}
+
+		var text string
+		switch part {
+		case LayoutTime:
+			text = formatTime(e.Time, enc.TimeFormat, enc.Location)
+		case LayoutCaller:
+			if e.Caller == "" {
+				continue
+			}
+			text = e.Caller + ":"
+		case LayoutLevel:
+			text = strings.TrimSuffix(e.Level.toString(), " ")
+		case LayoutModule:
+			if e.Module == "" {
+				continue
+			}
+			text = "[" + enc.Sanitizer.key(e.Module) + "]"
+		case LayoutMessage:
+			text = enc.Sanitizer.Message(e.Message)
+		default:
+			continue
+		}
+		if buf.Len() > start {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(text)
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+func (enc *TextEncoder) writeFields(buf *bytes.Buffer, fields []Field) {
+	for _, f := range fields {
+		if d, ok := f.Value.(*ErrorDetail); ok && d != nil {
+			for _, ef := range d.expand(f.Key) {
+				enc.writeField(buf, ef)
+			}
+			continue
+		}
+		enc.writeField(buf, f)
+	}
+}
+
+func (enc *TextEncoder) writeField(buf *bytes.Buffer, f Field) {
+	buf.WriteByte(' ')
+	buf.WriteString(textValue(&enc.Sanitizer, f.Key, 0))
+	buf.WriteByte('=')
+	buf.WriteString(textValue(&enc.Sanitizer, formatValue(f.Value), enc.Sanitizer.MaxFieldSize))
+}
+
+// JSONEncoder 以每行一个 JSON 对象的格式输出日志，例如：
+//
+//	{"time":"2006-01-02T15:04:05.000000Z07:00","level":"info","module":"payment","caller":"main.go:12","msg":"message","key":"value"}
+type JSONEncoder struct {
+	Sanitizer Sanitizer
+}
+
+// JSON 日志中的保留键。与保留键同名的字段会加上 JSONFieldPrefix 前缀，以免覆盖条目本身的值。
+const (
+	JSONTimeKey    = "time"
+	JSONLevelKey   = "level"
+	JSONModuleKey  = "module"
+	JSONCallerKey  = "caller"
+	JSONMessageKey = "msg"
+
+	// JSONFieldPrefix 是与保留键同名的字段的前缀，例如字段 "level" 输出为 "fields.level"。
+	JSONFieldPrefix = "fields."
+)
+
+// jsonFieldKey 返回字段在 JSON 中的键。
+func jsonFieldKey(key string) string {
+	switch key {
+	case JSONTimeKey, JSONLevelKey, JSONModuleKey, JSONCallerKey, JSONMessageKey:
+		return JSONFieldPrefix + key
+	}
+	return key
+}
+
+// Encode 实现 Encoder 接口。
+// JSON 字符串本身会转义控制字符，这里只负责移除 ANSI 序列和截断。
+func (enc *JSONEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	s := enc.Sanitizer
+	s.AllowControl = true
+
+	buf.WriteByte('{')
+	writeJSONKey(buf, JSONTimeKey, true)
+	writeJSONString(buf, e.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	writeJSONKey(buf, JSONLevelKey, false)
+	writeJSONString(buf, e.Level.String())
+	if e.Module != "" {
+		writeJSONKey(buf, JSONModuleKey, false)
+		writeJSONString(buf, s.key(e.Module))
+	}
+	if e.Caller != "" {
+		writeJSONKey(buf, JSONCallerKey, false)
+		writeJSONString(buf, e.Caller)
+	}
+	writeJSONKey(buf, JSONMessageKey, false)
+	writeJSONString(buf, s.Message(e.Message))
+	for _, f := range e.Fields {
+		writeJSONKey(buf, jsonFieldKey(s.key(f.Key)), false)
+		writeJSONValue(buf, &s, f.Value)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+func writeJSONKey(buf *bytes.Buffer, key string, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	_ = writeJSON(buf, s)
+}
+
+// writeJSONValue 追加字段值的 JSON 编码，无法编码或超长的值会退化为字符串。
+func writeJSONValue(buf *bytes.Buffer, s *Sanitizer, v any) {
+	switch val := v.(type) {
+	case string:
+		writeJSONString(buf, s.Field(val))
+		return
+	case error:
+		writeJSONString(buf, s.Field(val.Error()))
+		return
+	case time.Duration:
+		writeJSONString(buf, val.String())
+		return
+	case *ErrorDetail:
+		if val != nil {
+			if err := writeJSON(buf, val.sanitized(s)); err != nil {
+				writeJSONString(buf, s.Field(val.Message))
+			}
+			return
+		}
+	}
+
+	start := buf.Len()
+	if err := writeJSON(buf, v); err != nil {
+		buf.Truncate(start)
+		writeJSONString(buf, s.Field(fmt.Sprintf("%+v", v)))
+		return
+	}
+	if s.MaxFieldSize > 0 && buf.Len()-start > s.MaxFieldSize {
+		raw := buf.String()[start:]
+		buf.Truncate(start)
+		writeJSONString(buf, s.Field(raw))
+	}
+}
+
+// writeJSON 追加 v 的 JSON 编码，不转义 HTML 字符。
+func writeJSON(buf *bytes.Buffer, v any) error {
+	start := buf.Len()
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		buf.Truncate(start)
+		return err
+	}
+	// 去掉 json.Encoder 追加的换行
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+// formatValue 将字段值格式化为文本。
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case error:
+		return val.Error()
+	case nil:
+		return "<nil>"
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// textValue 按 s 清理文本格式中的字段名或字段值，max 为转义后的最大字节数。
+// 包含空白、等号、引号、反斜杠或控制字符的文本加上引号，引号内按 Go 字符串字面量转义，
+// 因此值的内容无法伪造出其他字段。
+func textValue(s *Sanitizer, v string, max int) string {
+	if s.StripANSI {
+		v = StripANSI(v)
+	}
+	if v != "" && !strings.ContainsAny(v, " =\"\\") && !needsEscape(v) {
+		return Truncate(v, max, s.marker())
+	}
+	esc := quoteRune
+	if s.AllowControl {
+		esc = quoteRuneAllowControl
+	}
+	return `"` + escapeTruncate(v, max, s.marker(), esc) + `"`
+}
+
+func quoteRune(r rune) string {
+	switch r {
+	case '"':
+		return `\"`
+	case '\\':
+		return `\\`
+	}
+	return escapeRune(r)
+}
+
+func quoteRuneAllowControl(r rune) string {
+	switch r {
+	case '"':
+		return `\"`
+	case '\\':
+		return `\\`
+	}
+	return string(r)
+}
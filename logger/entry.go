@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Field 是附加在日志条目上的键值对。
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F 创建一个字段。
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Entry 是交给 Encoder 编码的一条日志。
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Module  string
+	Caller  string
+	Fields  []Field
+}
+
+type fieldsKey struct{}
+
+// WithFields 返回携带附加字段的上下文，CtxLogger 输出日志时会带上这些字段。
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	existing := FieldsFromContext(ctx)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext 返回上下文中携带的字段。
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+	return fields
+}
+
+// NewRequestID 生成一个 16 位十六进制的随机请求 ID，供 middleware 和 interceptor 在请求缺少 ID 时使用。
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultErrorKey 是 Err 创建的字段使用的键。
+const DefaultErrorKey = "error"
+
+// maxErrorDepth 限制展开错误链的深度，防止循环引用。
+const maxErrorDepth = 32
+
+// ErrorAttributer 由能够提供结构化属性的错误实现，属性会出现在错误字段中。
+type ErrorAttributer interface {
+	ErrorAttrs() map[string]any
+}
+
+// StackTracer 由携带调用栈的错误实现。
+// 此外，带有 StackTrace() 方法的错误（如 github.com/pkg/errors）也会以 %+v 格式提取调用栈。
+type StackTracer interface {
+	Stack() []byte
+}
+
+// ErrorDetail 是展开后的错误信息。
+type ErrorDetail struct {
+	Message string         `json:"msg"`
+	Type    string         `json:"type"`
+	Stack   string         `json:"stack,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Causes  []*ErrorDetail `json:"causes,omitempty"`
+}
+
+// Err 返回键为 "error" 的错误字段，输出时会展开错误链。
+func Err(err error) Field {
+	return NamedErr(DefaultErrorKey, err)
+}
+
+// NamedErr 返回键为 key 的错误字段，输出时会展开错误链。
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Value: ExpandError(err)}
+}
+
+// WithError 返回携带错误字段的上下文。
+func WithError(ctx context.Context, err error) context.Context {
+	return WithFields(ctx, Err(err))
+}
+
+// ExpandError 沿 errors.Unwrap 和 errors.Join 展开 err，
+// 记录每一层的具体类型、调用栈和结构化属性。err 为 nil 时返回 nil。
+func ExpandError(err error) *ErrorDetail {
+	return expandError(err, 0)
+}
+
+func expandError(err error, depth int) *ErrorDetail {
+	if err == nil {
+		return nil
+	}
+
+	d := &ErrorDetail{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+		Stack:   errorStack(err),
+	}
+	if a, ok := err.(ErrorAttributer); ok {
+		d.Attrs = a.ErrorAttrs()
+	}
+	if depth >= maxErrorDepth {
+		return d
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, cause := range u.Unwrap() {
+			if c := expandError(cause, depth+1); c != nil {
+				d.Causes = append(d.Causes, c)
+			}
+		}
+	case interface{ Unwrap() error }:
+		if c := expandError(u.Unwrap(), depth+1); c != nil {
+			d.Causes = append(d.Causes, c)
+		}
+	}
+	return d
+}
+
+// errorStack 提取错误携带的调用栈。
+func errorStack(err error) string {
+	if st, ok := err.(StackTracer); ok {
+		return string(st.Stack())
+	}
+
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return ""
+	}
+	return strings.TrimLeft(fmt.Sprintf("%+v", m.Call(nil)[0].Interface()), "\n")
+}
+
+// Types 按深度优先顺序返回错误链中每一层的类型。
+func (d *ErrorDetail) Types() []string {
+	var types []string
+	d.walk(func(n *ErrorDetail) {
+		types = append(types, n.Type)
+	})
+	return types
+}
+
+// RootStack 返回错误链中最深处的调用栈，通常是错误产生的位置。
+func (d *ErrorDetail) RootStack() string {
+	var stack string
+	d.walk(func(n *ErrorDetail) {
+		if n.Stack != "" {
+			stack = n.Stack
+		}
+	})
+	return stack
+}
+
+// AllAttrs 合并错误链中所有层的属性，外层的属性优先。
+func (d *ErrorDetail) AllAttrs() map[string]any {
+	var attrs map[string]any
+	d.walk(func(n *ErrorDetail) {
+		for k, v := range n.Attrs {
+			if attrs == nil {
+				attrs = map[string]any{}
+			}
+			if _, ok := attrs[k]; !ok {
+				attrs[k] = v
+			}
+		}
+	})
+	return attrs
+}
+
+func (d *ErrorDetail) walk(fn func(*ErrorDetail)) {
+	fn(d)
+	for _, c := range d.Causes {
+		c.walk(fn)
+	}
+}
+
+// expand 将错误展开为多个文本字段：消息、类型链、属性和调用栈。
+func (d *ErrorDetail) expand(key string) []Field {
+	fields := []Field{
+		{Key: key, Value: d.Message},
+		{Key: key + ".types", Value: strings.Join(d.Types(), ",")},
+	}
+
+	attrs := d.AllAttrs()
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, Field{Key: key + "." + k, Value: attrs[k]})
+	}
+
+	if stack := d.RootStack(); stack != "" {
+		fields = append(fields, Field{Key: key + ".stack", Value: stack})
+	}
+	return fields
+}
+
+// sanitized 返回清理了消息、调用栈和字符串属性的副本。
+func (d *ErrorDetail) sanitized(s *Sanitizer) *ErrorDetail {
+	c := *d
+	c.Message = s.Field(d.Message)
+	c.Stack = s.Field(d.Stack)
+	if d.Attrs != nil {
+		c.Attrs = make(map[string]any, len(d.Attrs))
+		for k, v := range d.Attrs {
+			if str, ok := v.(string); ok {
+				v = s.Field(str)
+			}
+			c.Attrs[s.key(k)] = v
+		}
+	}
+	c.Causes = make([]*ErrorDetail, len(d.Causes))
+	for i, cause := range d.Causes {
+		c.Causes[i] = cause.sanitized(s)
+	}
+	return &c
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type paymentError struct {
+	orderID string
+}
+
+func (e *paymentError) Error() string {
+	return "payment declined"
+}
+
+func (e *paymentError) ErrorAttrs() map[string]any {
+	return map[string]any{"order_id": e.orderID}
+}
+
+func (e *paymentError) Stack() []byte {
+	return []byte("main.pay\n\tpay.go:10")
+}
+
+type frames []string
+
+func (f frames) Format(s fmt.State, verb rune) {
+	for _, frame := range f {
+		fmt.Fprintf(s, "\n%s", frame)
+	}
+}
+
+type tracedError struct{}
+
+func (tracedError) Error() string { return "traced" }
+
+func (tracedError) StackTrace() frames { return frames{"a.go:1", "b.go:2"} }
+
+func TestExpandError(t *testing.T) {
+	root := &paymentError{orderID: "A100"}
+	err := fmt.Errorf("checkout: %w", errors.Join(root, fs.ErrNotExist))
+
+	d := ExpandError(err)
+	assert.Equal(t, err.Error(), d.Message)
+	assert.Equal(t, []string{"*fmt.wrapError", "*errors.joinError", "*logger.paymentError", "*errors.errorString"}, d.Types())
+	assert.Equal(t, "main.pay\n\tpay.go:10", d.RootStack())
+	assert.Equal(t, map[string]any{"order_id": "A100"}, d.AllAttrs())
+
+	assert.Nil(t, ExpandError(nil))
+	assert.Equal(t, "a.go:1\nb.go:2", ExpandError(tracedError{}).Stack)
+}
+
+func TestErrField_Text(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithCaller(false))
+
+	err := fmt.Errorf("checkout: %w", &paymentError{orderID: "A100"})
+	l.CtxErrorf(WithError(context.Background(), err), "charge failed")
+
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+	assert.Contains(t, out, `error="checkout: payment declined"`)
+	assert.Contains(t, out, "error.types=*fmt.wrapError,*logger.paymentError")
+	assert.Contains(t, out, "error.order_id=A100")
+	assert.Contains(t, out, `error.stack="main.pay\n\tpay.go:10"`)
+}
+
+func TestErrField_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithEncoder(&JSONEncoder{}))
+
+	err := fmt.Errorf("checkout: %w", &paymentError{orderID: "A100"})
+	l.CtxErrorf(WithFields(context.Background(), NamedErr("cause", err)), "charge failed")
+
+	var m struct {
+		Cause ErrorDetail `json:"cause"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "checkout: payment declined", m.Cause.Message)
+	assert.Equal(t, "*fmt.wrapError", m.Cause.Type)
+	assert.Len(t, m.Cause.Causes, 1)
+	assert.Equal(t, "*logger.paymentError", m.Cause.Causes[0].Type)
+	assert.Equal(t, "A100", m.Cause.Causes[0].Attrs["order_id"])
+	assert.NotEmpty(t, m.Cause.Causes[0].Stack)
+}
+
+func TestErrField_JSONUnencodableAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithEncoder(&JSONEncoder{}))
+
+	d := &ErrorDetail{Message: "boom", Type: "*errors.errorString", Attrs: map[string]any{"ch": make(chan int)}}
+	l.CtxErrorf(WithFields(context.Background(), Field{Key: "error", Value: d}), "failed")
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "boom", m["error"])
+}
+
+func TestErrField_Redacted(t *testing.T) {
+	r, err := NewRedactor([]string{"token"}, []string{`\d{16}`}, "")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithEncoder(&JSONEncoder{}), WithRedactor(r))
+
+	err = fmt.Errorf("charge 4111111111111111: %w", &paymentError{orderID: "4111111111111111"})
+	d := ExpandError(err)
+	d.Causes[0].Attrs["token"] = "secret"
+	l.CtxErrorf(WithFields(context.Background(), Field{Key: "error", Value: d}), "failed")
+
+	out := buf.String()
+	assert.NotContains(t, out, "4111111111111111")
+	assert.NotContains(t, out, "secret")
+
+	var m struct {
+		Error ErrorDetail `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "charge ***: payment declined", m.Error.Message)
+	assert.Equal(t, "***", m.Error.Causes[0].Attrs["order_id"])
+	assert.Equal(t, "***", m.Error.Causes[0].Attrs["token"])
+	// 原错误不受影响
+	assert.Equal(t, "secret", d.Causes[0].Attrs["token"])
+}
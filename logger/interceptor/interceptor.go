@@ -0,0 +1,296 @@
+// Package interceptor 提供基于 logger 的 gRPC 拦截器，记录方法、对端、状态码、耗时和消息大小。
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// 日志字段和元数据的键。
+const (
+	DefaultRequestIDKey = "x-request-id"
+	TraceParentKey      = "traceparent"
+
+	RequestIDField = "request_id"
+	TraceIDField   = "trace_id"
+)
+
+type options struct {
+	logger       logger.FullLogger
+	levels       map[codes.Code]logger.Level
+	levelFunc    func(codes.Code) logger.Level
+	requestIDKey string
+}
+
+// Option 是拦截器的配置项。
+type Option func(*options)
+
+// WithLogger 设置拦截器使用的记录器，默认使用 logger.DefaultLogger()。
+func WithLogger(l logger.FullLogger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithLevel 设置状态码 code 对应的日志级别，优先于 WithLevelFunc。
+func WithLevel(code codes.Code, lv logger.Level) Option {
+	return func(o *options) {
+		o.levels[code] = lv
+	}
+}
+
+// WithLevelFunc 设置状态码到日志级别的映射，默认为 DefaultLevel。
+func WithLevelFunc(f func(codes.Code) logger.Level) Option {
+	return func(o *options) {
+		o.levelFunc = f
+	}
+}
+
+// WithRequestIDKey 设置请求 ID 所在的元数据键，默认为 x-request-id。
+func WithRequestIDKey(key string) Option {
+	return func(o *options) {
+		o.requestIDKey = strings.ToLower(key)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		levels:       map[codes.Code]logger.Level{},
+		levelFunc:    DefaultLevel,
+		requestIDKey: DefaultRequestIDKey,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) log() logger.FullLogger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return logger.DefaultLogger()
+}
+
+func (o *options) level(code codes.Code) logger.Level {
+	if lv, ok := o.levels[code]; ok {
+		return lv
+	}
+	return o.levelFunc(code)
+}
+
+// DefaultLevel 是默认的状态码到日志级别的映射：
+// OK 为信息级别，调用方导致的错误为警告级别，服务端错误为错误级别。
+func DefaultLevel(code codes.Code) logger.Level {
+	switch code {
+	case codes.OK:
+		return logger.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return logger.LevelWarn
+	default:
+		return logger.LevelError
+	}
+}
+
+// write 按状态码对应的级别输出一条调用日志。
+func (o *options) write(ctx context.Context, kind, method string, err error, fields ...logger.Field) {
+	code := status.Code(err)
+	ctx = logger.WithFields(ctx, fields...)
+	ctx = logger.WithFields(ctx, logger.F("grpc.code", code.String()))
+	if err != nil {
+		ctx = logger.WithFields(ctx, logger.F("grpc.error", status.Convert(err).Message()))
+	}
+
+	l := o.log()
+	format, args := "%s %s %s", []any{kind, method, code}
+	switch o.level(code) {
+	case logger.LevelTrace:
+		l.CtxTracef(ctx, format, args...)
+	case logger.LevelDebug:
+		l.CtxDebugf(ctx, format, args...)
+	case logger.LevelInfo:
+		l.CtxInfof(ctx, format, args...)
+	case logger.LevelNotice:
+		l.CtxNoticef(ctx, format, args...)
+	case logger.LevelWarn:
+		l.CtxWarnf(ctx, format, args...)
+	case logger.LevelFatal:
+		l.CtxFatalf(ctx, format, args...)
+	default:
+		l.CtxErrorf(ctx, format, args...)
+	}
+}
+
+// serverContext 从请求元数据中提取请求 ID 和跟踪 ID 放入上下文，缺少请求 ID 时生成一个。
+func (o *options) serverContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := first(md, o.requestIDKey)
+	if id == "" {
+		id = logger.NewRequestID()
+	}
+	fields := []logger.Field{logger.F(RequestIDField, id)}
+	if traceID := parseTraceParent(first(md, TraceParentKey)); traceID != "" {
+		fields = append(fields, logger.F(TraceIDField, traceID))
+	}
+	return logger.WithFields(ctx, fields...)
+}
+
+// clientContext 将上下文中的请求 ID 写入发出的元数据，使其在服务间传递。
+func (o *options) clientContext(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(o.requestIDKey)) > 0 {
+		return ctx
+	}
+	for _, f := range logger.FieldsFromContext(ctx) {
+		if id, ok := f.Value.(string); ok && f.Key == RequestIDField && id != "" {
+			return metadata.AppendToOutgoingContext(ctx, o.requestIDKey, id)
+		}
+	}
+	return ctx
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// parseTraceParent 从 W3C traceparent 中解析跟踪 ID。
+func parseTraceParent(v string) string {
+	parts := strings.Split(v, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+	return parts[1]
+}
+
+func peerAddr(p *peer.Peer) string {
+	if p == nil || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+// size 返回 protobuf 消息编码后的字节数，非 protobuf 消息返回 0。
+func size(msg any) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// UnaryServerInterceptor 返回记录一元调用的服务端拦截器。
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = o.serverContext(ctx)
+
+		resp, err := handler(ctx, req)
+
+		p, _ := peer.FromContext(ctx)
+		o.write(ctx, "unary", info.FullMethod, err,
+			logger.F("grpc.method", info.FullMethod),
+			logger.F("peer", peerAddr(p)),
+			logger.F("duration", time.Since(start)),
+			logger.F("req_size", size(req)),
+			logger.F("resp_size", size(resp)),
+		)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回记录流式调用的服务端拦截器。
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ws := &serverStream{ServerStream: ss, ctx: o.serverContext(ss.Context())}
+
+		err := handler(srv, ws)
+
+		p, _ := peer.FromContext(ws.ctx)
+		o.write(ws.ctx, "stream", info.FullMethod, err,
+			logger.F("grpc.method", info.FullMethod),
+			logger.F("peer", peerAddr(p)),
+			logger.F("duration", time.Since(start)),
+			logger.F("msgs_received", ws.recv.count.Load()),
+			logger.F("req_size", ws.recv.bytes.Load()),
+			logger.F("msgs_sent", ws.sent.count.Load()),
+			logger.F("resp_size", ws.sent.bytes.Load()),
+		)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 返回记录一元调用的客户端拦截器。
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx = o.clientContext(ctx)
+		var p peer.Peer
+
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(&p))...)
+
+		respSize := 0
+		if err == nil {
+			respSize = size(reply)
+		}
+		o.write(ctx, "client unary", method, err,
+			logger.F("grpc.method", method),
+			logger.F("peer", peerAddr(&p)),
+			logger.F("duration", time.Since(start)),
+			logger.F("req_size", size(req)),
+			logger.F("resp_size", respSize),
+		)
+		return err
+	}
+}
+
+// StreamClientInterceptor 返回记录流式调用的客户端拦截器，在流结束时输出日志。
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx = o.clientContext(ctx)
+		p := &peer.Peer{}
+
+		cs, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			o.write(ctx, "client stream", method, err,
+				logger.F("grpc.method", method),
+				logger.F("duration", time.Since(start)),
+			)
+			return nil, err
+		}
+
+		return &clientStream{
+			ClientStream:  cs,
+			serverStreams: desc.ServerStreams,
+			finish: func(cs *clientStream, err error) {
+				o.write(ctx, "client stream", method, err,
+					logger.F("grpc.method", method),
+					logger.F("peer", peerAddr(p)),
+					logger.F("duration", time.Since(start)),
+					logger.F("msgs_sent", cs.sent.count.Load()),
+					logger.F("req_size", cs.sent.bytes.Load()),
+					logger.F("msgs_received", cs.recv.count.Load()),
+					logger.F("resp_size", cs.recv.bytes.Load()),
+				)
+			},
+		}, nil
+	}
+}
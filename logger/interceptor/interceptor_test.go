@@ -0,0 +1,201 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// syncBuffer 是并发安全的 bytes.Buffer。
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) entries(t *testing.T) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m))
+		entries = append(entries, m)
+	}
+	return entries
+}
+
+func newJSONLogger(w *syncBuffer) logger.FullLogger {
+	return logger.NewLogger(logger.WithOutput(w), logger.WithEncoder(&logger.JSONEncoder{}))
+}
+
+func setup(t *testing.T, serverOpts, clientOpts []Option) healthpb.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(serverOpts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(serverOpts...)),
+	)
+	hs := health.NewServer()
+	hs.SetServingStatus("payment", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(clientOpts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(clientOpts...)),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	var serverLog, clientLog syncBuffer
+	client := setup(t,
+		[]Option{WithLogger(newJSONLogger(&serverLog))},
+		[]Option{WithLogger(newJSONLogger(&clientLog)), WithLevel(codes.NotFound, logger.LevelDebug)},
+	)
+
+	ctx := logger.WithFields(context.Background(), logger.F(RequestIDField, "rid-1"))
+	ctx = metadata.AppendToOutgoingContext(ctx, TraceParentKey, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	req := &healthpb.HealthCheckRequest{Service: "payment"}
+	_, err := client.Check(ctx, req)
+	assert.NoError(t, err)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Error(t, err)
+
+	server := serverLog.entries(t)
+	assert.Len(t, server, 2)
+	assert.Equal(t, "info", server[0]["level"])
+	assert.Equal(t, "unary /grpc.health.v1.Health/Check OK", server[0]["msg"])
+	assert.Equal(t, "/grpc.health.v1.Health/Check", server[0]["grpc.method"])
+	assert.Equal(t, "OK", server[0]["grpc.code"])
+	assert.Equal(t, "rid-1", server[0][RequestIDField])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server[0][TraceIDField])
+	assert.Equal(t, "bufconn", server[0]["peer"])
+	assert.NotEmpty(t, server[0]["duration"])
+	assert.Greater(t, server[0]["req_size"], float64(0))
+	assert.Greater(t, server[0]["resp_size"], float64(0))
+	assert.Equal(t, "warn", server[1]["level"])
+	assert.Equal(t, "NotFound", server[1]["grpc.code"])
+	assert.NotEmpty(t, server[1]["grpc.error"])
+
+	client1 := clientLog.entries(t)
+	assert.Len(t, client1, 2)
+	assert.Equal(t, "client unary /grpc.health.v1.Health/Check OK", client1[0]["msg"])
+	assert.Equal(t, "rid-1", client1[0][RequestIDField])
+	assert.Equal(t, "debug", client1[1]["level"])
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	var serverLog, clientLog syncBuffer
+	client := setup(t,
+		[]Option{WithLogger(newJSONLogger(&serverLog))},
+		[]Option{WithLogger(newJSONLogger(&clientLog))},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "payment"})
+	assert.NoError(t, err)
+	resp, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Error(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(serverLog.entries(t)) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	server := serverLog.entries(t)[0]
+	assert.Equal(t, "/grpc.health.v1.Health/Watch", server["grpc.method"])
+	assert.Equal(t, "Canceled", server["grpc.code"])
+	assert.Equal(t, float64(1), server["msgs_received"])
+	assert.Equal(t, float64(1), server["msgs_sent"])
+	assert.NotEmpty(t, server[RequestIDField])
+
+	client1 := clientLog.entries(t)
+	assert.Len(t, client1, 1)
+	assert.Equal(t, "warn", client1[0]["level"])
+	assert.Equal(t, "Canceled", client1[0]["grpc.code"])
+	assert.Equal(t, float64(1), client1[0]["msgs_sent"])
+	assert.Equal(t, float64(1), client1[0]["msgs_received"])
+}
+
+// fakeClientStream 接受任意数量的发送，在收到 n 条消息后返回 io.EOF。
+type fakeClientStream struct {
+	grpc.ClientStream
+	n int
+}
+
+func (s *fakeClientStream) SendMsg(any) error { return nil }
+
+func (s *fakeClientStream) RecvMsg(any) error {
+	if s.n == 0 {
+		return io.EOF
+	}
+	s.n--
+	return nil
+}
+
+func TestClientStream_ConcurrentSendRecv(t *testing.T) {
+	finished := make(chan [2]int64, 1)
+	cs := &clientStream{
+		ClientStream:  &fakeClientStream{n: 100},
+		serverStreams: true,
+		finish: func(cs *clientStream, err error) {
+			finished <- [2]int64{cs.sent.count.Load(), cs.recv.count.Load()}
+		},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			assert.NoError(t, cs.SendMsg(&healthpb.HealthCheckRequest{Service: "payment"}))
+		}
+	}()
+	for cs.RecvMsg(&healthpb.HealthCheckResponse{}) == nil {
+	}
+	wg.Wait()
+
+	counts := <-finished
+	assert.LessOrEqual(t, counts[0], int64(200))
+	assert.Equal(t, int64(100), counts[1])
+	assert.Equal(t, int64(200), cs.sent.count.Load())
+	assert.Equal(t, int64(200*len("payment")+400), cs.sent.bytes.Load())
+}
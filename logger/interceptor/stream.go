@@ -0,0 +1,91 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// counter 统计流中的消息数和字节数。双向流的收发可能在不同的 goroutine 中进行，因此使用原子计数。
+type counter struct {
+	count atomic.Int64
+	bytes atomic.Int64
+}
+
+func (c *counter) add(msg any) {
+	c.count.Add(1)
+	c.bytes.Add(int64(size(msg)))
+}
+
+// serverStream 替换上下文并统计收发的消息。
+type serverStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent counter
+	recv counter
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.add(m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recv.add(m)
+	}
+	return err
+}
+
+// clientStream 统计收发的消息，并在流结束时调用 finish。
+// 流以 RecvMsg 返回错误（包括 io.EOF）为结束标志，调用方未读到流末尾时不会输出日志。
+type clientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	finish        func(*clientStream, error)
+	once          sync.Once
+	sent          counter
+	recv          counter
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.add(m)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.recv.add(m)
+		if !s.serverStreams {
+			s.done(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.done(nil)
+	default:
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) done(err error) {
+	s.once.Do(func() {
+		s.finish(s, err)
+	})
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DefaultJournalSocket 是 systemd-journald 原生协议的套接字路径。
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// ErrJournalUnsupported 表示当前平台不支持 journald 原生协议。
+var ErrJournalUnsupported = errors.New("logger: journald is not supported on this platform")
+
+// JournalPriority 返回级别 lv 对应的 syslog 优先级，即 journald 的 PRIORITY 字段。
+func JournalPriority(lv Level) int {
+	switch lv {
+	case LevelFatal:
+		return 2 // crit
+	case LevelError:
+		return 3 // err
+	case LevelWarn:
+		return 4 // warning
+	case LevelNotice:
+		return 5 // notice
+	case LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// JournalFieldPrefix 是与 journald 保留字段同名的附加字段添加的前缀。
+const JournalFieldPrefix = "FIELD_"
+
+// journalReserved 是 JournalEncoder 自身写入或对 journald 有特殊含义的字段。
+var journalReserved = map[string]bool{
+	"MESSAGE": true, "MESSAGE_ID": true, "PRIORITY": true, "ERRNO": true, "TID": true,
+	"CODE_FILE": true, "CODE_LINE": true, "CODE_FUNC": true, "DOCUMENTATION": true,
+	"INVOCATION_ID": true, "USER_INVOCATION_ID": true, "LOGGER_MODULE": true,
+	"SYSLOG_FACILITY": true, "SYSLOG_IDENTIFIER": true, "SYSLOG_PID": true,
+	"SYSLOG_TIMESTAMP": true, "SYSLOG_RAW": true,
+}
+
+// JournalFieldName 将字段名转换为合法的 journald 字段名：
+// 字母转为大写，其他字符替换为下划线，去掉开头的下划线和数字，最长 64 个字符。
+// 因此附加字段不会成为以下划线开头的受信字段；与保留字段同名时加上 JournalFieldPrefix。
+// 无法转换时返回空字符串。
+func JournalFieldName(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if b.Len() > 0 {
+				b.WriteRune(r)
+			}
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('_')
+			}
+		}
+	}
+	name := b.String()
+	if journalReserved[name] {
+		name = JournalFieldPrefix + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// JournalEncoder 按 journald 原生协议编码日志条目，与 JournalWriter 配合使用：
+//
+//	w, err := logger.NewJournalWriter("")
+//	l := logger.NewLogger(logger.WithEncoder(&logger.JournalEncoder{Identifier: "app"}), logger.WithOutput(w))
+//
+// 消息写入 MESSAGE，级别写入 PRIORITY，模块写入 LOGGER_MODULE，调用位置写入 CODE_FILE 和 CODE_LINE，
+// 附加字段的名称经 JournalFieldName 转换后写入，无法转换的字段被忽略。
+// 通过配置使用 journald 输出时必须选择此编码器。
+type JournalEncoder struct {
+	// Identifier 是 SYSLOG_IDENTIFIER 字段，为空时由 journald 使用进程名。
+	Identifier string
+	Sanitizer  Sanitizer
+}
+
+// Encode 实现 Encoder 接口。
+func (enc *JournalEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	s := enc.Sanitizer
+	s.AllowControl = true
+
+	writeJournalField(buf, "MESSAGE", s.Message(e.Message))
+	writeJournalField(buf, "PRIORITY", strconv.Itoa(JournalPriority(e.Level)))
+	if enc.Identifier != "" {
+		writeJournalField(buf, "SYSLOG_IDENTIFIER", enc.Identifier)
+	}
+	if e.Module != "" {
+		writeJournalField(buf, "LOGGER_MODULE", s.key(e.Module))
+	}
+	if e.Caller != "" {
+		if i := strings.LastIndexByte(e.Caller, ':'); i > 0 {
+			writeJournalField(buf, "CODE_FILE", e.Caller[:i])
+			writeJournalField(buf, "CODE_LINE", e.Caller[i+1:])
+		}
+	}
+	for _, f := range e.Fields {
+		if d, ok := f.Value.(*ErrorDetail); ok && d != nil {
+			for _, ef := range d.expand(f.Key) {
+				enc.writeField(buf, &s, ef)
+			}
+			continue
+		}
+		enc.writeField(buf, &s, f)
+	}
+	return nil
+}
+
+func (enc *JournalEncoder) writeField(buf *bytes.Buffer, s *Sanitizer, f Field) {
+	if name := JournalFieldName(f.Key); name != "" {
+		writeJournalField(buf, name, s.Field(formatValue(f.Value)))
+	}
+}
+
+// writeJournalField 追加一个字段。不含换行的值写作 NAME=value，
+// 否则写作名称、换行、64 位小端长度和原始值。
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// JournalWriter 将每次 Write 的内容作为一个数据报发送给 journald。
+// 内容超出数据报大小限制时，改为写入密封的 memfd 并传递其文件描述符。
+type JournalWriter struct {
+	conn *net.UnixConn
+}
+
+// NewJournalWriter 连接 journald 的套接字 path，为空时使用 DefaultJournalSocket。
+func NewJournalWriter(path string) (*JournalWriter, error) {
+	if path == "" {
+		path = DefaultJournalSocket
+	}
+	conn, err := dialJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	return &JournalWriter{conn: conn}, nil
+}
+
+// Write 发送一条按原生协议编码的条目。
+func (w *JournalWriter) Write(p []byte) (int, error) {
+	_, err := w.conn.Write(p)
+	if err != nil && isMessageTooLarge(err) {
+		err = sendJournalMemfd(w.conn, p)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close 关闭套接字。
+func (w *JournalWriter) Close() error {
+	return w.conn.Close()
+}
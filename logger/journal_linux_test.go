@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+// listenJournal 创建一个模拟 journald 的套接字。
+func listenJournal(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return path, conn
+}
+
+// receiveJournal 读取一条条目，通过 memfd 传递时读取文件内容。
+func receiveJournal(t *testing.T, conn *net.UnixConn) ([]byte, bool) {
+	t.Helper()
+	buf := make([]byte, 64<<10)
+	oob := make([]byte, unix.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	assert.NoError(t, err)
+	if oobn == 0 {
+		return buf[:n], false
+	}
+
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	assert.NoError(t, err)
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	assert.NoError(t, err)
+	file := os.NewFile(uintptr(fds[0]), "memfd")
+	defer file.Close()
+
+	seals, err := unix.FcntlInt(file.Fd(), unix.F_GET_SEALS, 0)
+	assert.NoError(t, err)
+	assert.NotZero(t, seals&unix.F_SEAL_WRITE)
+
+	// 发送方与接收方共享文件偏移，journald 同样从头映射文件。
+	_, err = file.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(file)
+	assert.NoError(t, err)
+	return data, true
+}
+
+func TestJournalWriter(t *testing.T) {
+	path, conn := listenJournal(t)
+	w, err := NewJournalWriter(path)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	l := NewLogger(WithEncoder(&JournalEncoder{Identifier: "test"}), WithOutput(w), WithMetrics(nil))
+	l.Errorf("failed %d", 1)
+
+	data, viaMemfd := receiveJournal(t, conn)
+	assert.False(t, viaMemfd)
+	fields := parseJournal(t, data)
+	assert.Equal(t, "failed 1", fields["MESSAGE"])
+	assert.Equal(t, "3", fields["PRIORITY"])
+	assert.Equal(t, "test", fields["SYSLOG_IDENTIFIER"])
+	assert.Equal(t, "journal_linux_test.go", fields["CODE_FILE"])
+}
+
+func TestJournalWriter_Memfd(t *testing.T) {
+	path, conn := listenJournal(t)
+	w, err := NewJournalWriter(path)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	// 超出套接字发送缓冲区的数据报会返回 EMSGSIZE。
+	large := strings.Repeat("x", 4<<20)
+	l := NewLogger(WithEncoder(&JournalEncoder{}), WithOutput(w), WithMetrics(nil))
+	l.Info(large)
+
+	data, viaMemfd := receiveJournal(t, conn)
+	assert.True(t, viaMemfd)
+	assert.Equal(t, large, parseJournal(t, data)["MESSAGE"])
+}
+
+func TestNewJournalWriter_Missing(t *testing.T) {
+	_, err := NewJournalWriter(filepath.Join(t.TempDir(), "missing.sock"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package logger
+
+import "net"
+
+func dialJournal(string) (*net.UnixConn, error) {
+	return nil, ErrJournalUnsupported
+}
+
+func isMessageTooLarge(error) bool {
+	return false
+}
+
+func sendJournalMemfd(*net.UnixConn, []byte) error {
+	return ErrJournalUnsupported
+}
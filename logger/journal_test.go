@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parseJournal 解析原生协议编码的条目。
+func parseJournal(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	for len(b) > 0 {
+		i := bytes.IndexAny(b, "=\n")
+		if !assert.True(t, i > 0, "malformed entry %q", b) {
+			return fields
+		}
+		name := string(b[:i])
+		if b[i] == '=' {
+			end := bytes.IndexByte(b[i:], '\n')
+			fields[name] = string(b[i+1 : i+end])
+			b = b[i+end+1:]
+			continue
+		}
+		b = b[i+1:]
+		n := binary.LittleEndian.Uint64(b[:8])
+		fields[name] = string(b[8 : 8+n])
+		assert.Equal(t, byte('\n'), b[8+n])
+		b = b[9+n:]
+	}
+	return fields
+}
+
+func TestJournalFieldName(t *testing.T) {
+	assert.Equal(t, "USER_ID", JournalFieldName("user.id"))
+	assert.Equal(t, "REQUEST_ID", JournalFieldName("request-id"))
+	assert.Equal(t, "TRUSTED", JournalFieldName("__trusted"))
+	assert.Equal(t, "X1", JournalFieldName("1x1"))
+	assert.Equal(t, "", JournalFieldName("中文"))
+	assert.Len(t, JournalFieldName(strings.Repeat("a", 100)), 64)
+
+	assert.Equal(t, "FIELD_MESSAGE", JournalFieldName("message"))
+	assert.Equal(t, "FIELD_PRIORITY", JournalFieldName("priority"))
+	assert.Equal(t, "FIELD_SYSLOG_IDENTIFIER", JournalFieldName("_syslog.identifier"))
+	assert.Equal(t, "FIELD_CODE_LINE", JournalFieldName("code_line"))
+	assert.Equal(t, "MESSAGE_TEXT", JournalFieldName("message_text"))
+}
+
+func TestJournalEncoder(t *testing.T) {
+	enc := &JournalEncoder{Identifier: "app"}
+	var buf bytes.Buffer
+	assert.NoError(t, enc.Encode(&buf, &Entry{
+		Time:    time.Now(),
+		Level:   LevelWarn,
+		Message: "line1\nline2",
+		Module:  "db",
+		Caller:  "main.go:12",
+		Fields: []Field{
+			F("user.id", 7),
+			F("message", "user message"),
+			F("_PID", 1),
+			F("中文", "ignored"),
+			Err(errors.New("boom")),
+		},
+	}))
+
+	fields := parseJournal(t, buf.Bytes())
+	assert.Equal(t, map[string]string{
+		"MESSAGE":           "line1\nline2",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "app",
+		"LOGGER_MODULE":     "db",
+		"CODE_FILE":         "main.go",
+		"CODE_LINE":         "12",
+		"USER_ID":           "7",
+		"FIELD_MESSAGE":     "user message",
+		"PID":               "1",
+		"ERROR":             "boom",
+		"ERROR_TYPES":       "*errors.errorString",
+	}, fields)
+}
+
+func TestJournalPriority(t *testing.T) {
+	assert.Equal(t, 2, JournalPriority(LevelFatal))
+	assert.Equal(t, 3, JournalPriority(LevelError))
+	assert.Equal(t, 6, JournalPriority(LevelInfo))
+	assert.Equal(t, 7, JournalPriority(LevelTrace))
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TextEncoder.Order 中的各部分。
+const (
+	LayoutTime    = "time"
+	LayoutCaller  = "caller"
+	LayoutLevel   = "level"
+	LayoutModule  = "module"
+	LayoutMessage = "message"
+	LayoutFields  = "fields"
+)
+
+// DefaultLayout 是 TextEncoder 默认的输出顺序。
+var DefaultLayout = []string{LayoutTime, LayoutCaller, LayoutLevel, LayoutModule, LayoutMessage, LayoutFields}
+
+// 时间格式。除以下常量外，TextEncoder.TimeFormat 也可以是任意 time 包的布局。
+const (
+	DefaultTimeFormat      = "2006/01/02 15:04:05.000000"
+	TimeFormatRFC3339Milli = "2006-01-02T15:04:05.000Z07:00"
+	// TimeFormatUnix 等输出 Unix 时间戳，分别以秒、毫秒、微秒和纳秒为单位。
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unixmilli"
+	TimeFormatUnixMicro = "unixmicro"
+	TimeFormatUnixNano  = "unixnano"
+)
+
+// formatTime 按 format 和 loc 格式化 t，format 为空时使用 DefaultTimeFormat，loc 为空时使用本地时区。
+func formatTime(t time.Time, format string, loc *time.Location) string {
+	switch format {
+	case "":
+		format = DefaultTimeFormat
+	case TimeFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeFormatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case TimeFormatUnixMicro:
+		return strconv.FormatInt(t.UnixMicro(), 10)
+	case TimeFormatUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format(format)
+}
+
+// TextTemplateData 是 TextEncoder.Template 的数据，文本均已按 Sanitizer 处理。
+type TextTemplateData struct {
+	// Time 是按 TimeFormat 和 Location 格式化的时间。
+	Time string
+	// Level 是级别，在模板中输出为小写名称，可以用 upper 函数转为大写。
+	Level   Level
+	Module  string
+	Caller  string
+	Message string
+	// Fields 是格式化后的附加字段，例如 "key=value key2=value2"。
+	Fields string
+	// Entry 是日志条目的副本，其中的消息、模块、字段名和非数值的字段值已按 Sanitizer 处理。
+	Entry *Entry
+}
+
+// sanitizedEntry 返回按 s 处理后的条目副本。数值、布尔值、时长和时间保持原样，
+// 错误详情清理后保留结构，其他字段值格式化为清理后的字符串。
+func sanitizedEntry(s *Sanitizer, e *Entry) *Entry {
+	c := *e
+	c.Message = s.Message(e.Message)
+	c.Module = s.key(e.Module)
+	c.Fields = make([]Field, len(e.Fields))
+	for i, f := range e.Fields {
+		c.Fields[i] = Field{Key: s.key(f.Key), Value: sanitizedValue(s, f.Value)}
+	}
+	return &c
+}
+
+func sanitizedValue(s *Sanitizer, v any) any {
+	switch val := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, time.Duration, time.Time, Level:
+		return v
+	case *ErrorDetail:
+		if val != nil {
+			return val.sanitized(s)
+		}
+		return v
+	default:
+		return s.Field(formatValue(v))
+	}
+}
+
+// textTemplateFuncs 是 ParseTextTemplate 提供的模板函数。
+var textTemplateFuncs = template.FuncMap{
+	"upper": func(v any) string { return strings.ToUpper(formatValue(v)) },
+	"lower": func(v any) string { return strings.ToLower(formatValue(v)) },
+	"pad": func(width int, v any) string {
+		s := formatValue(v)
+		if n := width - len([]rune(s)); n > 0 {
+			s += strings.Repeat(" ", n)
+		}
+		return s
+	},
+}
+
+// ParseTextTemplate 解析 TextEncoder 使用的模板，例如：
+//
+//	{{.Time}} {{.Level | upper | pad 6}} {{with .Module}}[{{.}}] {{end}}{{.Message}}{{with .Fields}} {{.}}{{end}}
+//
+// 模板可以使用 upper、lower 和 pad 函数。输出末尾没有换行时会自动追加。
+func ParseTextTemplate(text string) (*template.Template, error) {
+	return template.New("logger").Funcs(textTemplateFuncs).Parse(text)
+}
+
+func (enc *TextEncoder) encodeTemplate(buf *bytes.Buffer, e *Entry) error {
+	var fields bytes.Buffer
+	enc.writeFields(&fields, e.Fields)
+
+	data := &TextTemplateData{
+		Time:    formatTime(e.Time, enc.TimeFormat, enc.Location),
+		Level:   e.Level,
+		Module:  enc.Sanitizer.key(e.Module),
+		Caller:  e.Caller,
+		Message: enc.Sanitizer.Message(e.Message),
+		Fields:  strings.TrimPrefix(fields.String(), " "),
+		Entry:   sanitizedEntry(&enc.Sanitizer, e),
+	}
+	start := buf.Len()
+	if err := enc.Template.Execute(buf, data); err != nil {
+		buf.Truncate(start)
+		return err
+	}
+	if buf.Len() == start || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return nil
+}
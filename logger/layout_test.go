@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/favbox/pkg/object"
+	"github.com/stretchr/testify/assert"
+)
+
+var layoutEntry = &Entry{
+	Time:    time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC),
+	Level:   LevelWarn,
+	Message: "disk almost full",
+	Module:  "storage",
+	Caller:  "disk.go:42",
+	Fields:  []Field{F("used", "93%"), F("mount", "/data")},
+}
+
+func encodeText(t *testing.T, enc *TextEncoder, e *Entry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, enc.Encode(&buf, e))
+	return buf.String()
+}
+
+func TestTextEncoder_Layout(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+
+	assert.Equal(t, "2024/05/01 10:00:00.123456 disk.go:42: [Warn] [storage] disk almost full used=93% mount=/data\n",
+		encodeText(t, &TextEncoder{Location: time.UTC}, layoutEntry))
+	assert.Equal(t, "2024-05-01T18:00:00.123+08:00 [Warn] disk almost full used=93% mount=/data\n",
+		encodeText(t, &TextEncoder{
+			TimeFormat: TimeFormatRFC3339Milli,
+			Location:   shanghai,
+			Order:      []string{LayoutTime, LayoutLevel, LayoutMessage, LayoutFields},
+		}, layoutEntry))
+	assert.Equal(t, "[Warn] 1714557600123 [storage] disk almost full\n",
+		encodeText(t, &TextEncoder{
+			TimeFormat: TimeFormatUnixMilli,
+			Order:      []string{LayoutLevel, LayoutTime, LayoutModule, LayoutMessage},
+		}, layoutEntry))
+	assert.Equal(t, "1714557600 [Info] \n",
+		encodeText(t, &TextEncoder{TimeFormat: TimeFormatUnix}, &Entry{Time: layoutEntry.Time, Level: LevelInfo}))
+}
+
+func TestTextEncoder_Template(t *testing.T) {
+	tmpl, err := ParseTextTemplate(`{{.Time}} {{.Level | upper | pad 6}}{{with .Module}}[{{.}}] {{end}}{{.Message}}{{with .Fields}} | {{.}}{{end}}`)
+	assert.NoError(t, err)
+	enc := &TextEncoder{TimeFormat: time.RFC3339, Location: time.UTC, Template: tmpl}
+
+	assert.Equal(t, "2024-05-01T10:00:00Z WARN  [storage] disk almost full | used=93% mount=/data\n",
+		encodeText(t, enc, layoutEntry))
+	assert.Equal(t, "2024-05-01T10:00:00Z ERROR plain\n",
+		encodeText(t, enc, &Entry{Time: layoutEntry.Time, Level: LevelError, Message: "plain"}))
+
+	tmpl, err = ParseTextTemplate(`{{.Missing}}`)
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.Error(t, (&TextEncoder{Template: tmpl}).Encode(&buf, layoutEntry))
+	assert.Zero(t, buf.Len())
+}
+
+func TestTextEncoder_TemplateEntrySanitized(t *testing.T) {
+	tmpl, err := ParseTextTemplate(`{{.Entry.Module}} {{.Entry.Message}}{{range .Entry.Fields}} {{.Key}}={{.Value}}{{end}}`)
+	assert.NoError(t, err)
+	enc := &TextEncoder{Template: tmpl}
+
+	e := &Entry{
+		Level:   LevelInfo,
+		Module:  "db\x1b[2J",
+		Message: "hi\x1b[31m\nfake",
+		Fields:  []Field{F("k\ney", "v\x1b]0;x\x07"), F("n", 3), F("err", errors.New("bad\rthing"))},
+	}
+	out := encodeText(t, enc, e)
+	assert.NotContains(t, out, "\x1b")
+	assert.NotContains(t, out, "\r")
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+	assert.Contains(t, out, " n=3")
+	// 原条目不受影响
+	assert.Equal(t, "hi\x1b[31m\nfake", e.Message)
+}
+
+func TestNewLoggerFromConfig_TextLayout(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := NewLoggerFromConfig(&object.HashMap{
+		"caller": false,
+		"encoder": map[string]any{
+			"time_format": "unix",
+			"order":       []any{"level", "message", "fields"},
+		},
+	})
+	assert.NoError(t, err)
+	l.SetOutput(&buf)
+	l.CtxInfof(WithFields(context.Background(), F("k", "v")), "hello")
+	assert.Equal(t, "[Info] hello k=v\n", buf.String())
+
+	_, err = NewLoggerFromConfig(&object.HashMap{
+		"encoder": map[string]any{
+			"timezone": "Mars/Olympus",
+			"order":    []any{"level", "colour"},
+			"template": "{{.Message",
+		},
+	})
+	assert.ErrorContains(t, err, "encoder.timezone")
+	assert.ErrorContains(t, err, "encoder.order[1]")
+	assert.ErrorContains(t, err, "encoder.template")
+
+	_, err = NewLoggerFromConfig(&object.HashMap{
+		"encoder": map[string]any{"type": "json", "order": []any{"level"}},
+	})
+	assert.ErrorContains(t, err, "encoder.order: is only supported by the text encoder")
+}
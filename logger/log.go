@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// Fatal 调用默认记录器的 Fatal 方法，然后调用 os.Exit(1)。
+func Fatal(v ...any) {
+	logger.Fatal(v...)
+}
+
+// Error 调用默认记录器的 Error 方法。
+func Error(v ...any) {
+	logger.Error(v...)
+}
+
+// Warn 调用默认记录器的 Warn 方法。
+func Warn(v ...any) {
+	logger.Warn(v...)
+}
+
+// Notice 调用默认记录器的 Notice 方法。
+func Notice(v ...any) {
+	logger.Notice(v...)
+}
+
+// Info 调用默认记录器的 Info 方法。
+func Info(v ...any) {
+	logger.Info(v...)
+}
+
+// Debug 调用默认记录器的 Debug 方法。
+func Debug(v ...any) {
+	logger.Debug(v...)
+}
+
+// Trace 调用默认记录器的 Trace 方法。
+func Trace(v ...any) {
+	logger.Trace(v...)
+}
+
+// Fatalf 调用默认记录器的 Fatalf 方法，然后调用 os.Exit(1)。
+func Fatalf(format string, v ...any) {
+	logger.Fatalf(format, v...)
+}
+
+// Errorf 调用默认记录器的 Errorf 方法。
+func Errorf(format string, v ...any) {
+	logger.Errorf(format, v...)
+}
+
+// Warnf 调用默认记录器的 Warnf 方法。
+func Warnf(format string, v ...any) {
+	logger.Warnf(format, v...)
+}
+
+// Noticef 调用默认记录器的 Noticef 方法。
+func Noticef(format string, v ...any) {
+	logger.Noticef(format, v...)
+}
+
+// Infof 调用默认记录器的 Infof 方法。
+func Infof(format string, v ...any) {
+	logger.Infof(format, v...)
+}
+
+// Debugf 调用默认记录器的 Debugf 方法。
+func Debugf(format string, v ...any) {
+	logger.Debugf(format, v...)
+}
+
+// Tracef 调用默认记录器的 Tracef 方法。
+func Tracef(format string, v ...any) {
+	logger.Tracef(format, v...)
+}
+
+// CtxFatalf 调用默认记录器的 CtxFatalf 方法，然后调用 os.Exit(1)。
+func CtxFatalf(ctx context.Context, format string, v ...any) {
+	logger.CtxFatalf(ctx, format, v...)
+}
+
+// CtxErrorf 调用默认记录器的 CtxErrorf 方法。
+func CtxErrorf(ctx context.Context, format string, v ...any) {
+	logger.CtxErrorf(ctx, format, v...)
+}
+
+// CtxWarnf 调用默认记录器的 CtxWarnf 方法。
+func CtxWarnf(ctx context.Context, format string, v ...any) {
+	logger.CtxWarnf(ctx, format, v...)
+}
+
+// CtxNoticef 调用默认记录器的 CtxNoticef 方法。
+func CtxNoticef(ctx context.Context, format string, v ...any) {
+	logger.CtxNoticef(ctx, format, v...)
+}
+
+// CtxInfof 调用默认记录器的 CtxInfof 方法。
+func CtxInfof(ctx context.Context, format string, v ...any) {
+	logger.CtxInfof(ctx, format, v...)
+}
+
+// CtxDebugf 调用默认记录器的 CtxDebugf 方法。
+func CtxDebugf(ctx context.Context, format string, v ...any) {
+	logger.CtxDebugf(ctx, format, v...)
+}
+
+// CtxTracef 调用默认记录器的 CtxTracef 方法。
+func CtxTracef(ctx context.Context, format string, v ...any) {
+	logger.CtxTracef(ctx, format, v...)
+}
+
+// logAt 通过 l 以级别 lv 输出日志。
+// skip 为 logAt 的调用方与用户调用的包级函数之间额外的栈帧数，
+// 用于修正 NewLogger 创建的记录器记录的调用位置。
+func logAt(ctx context.Context, l FullLogger, skip int, lv Level, format string, v ...any) {
+	if ll, ok := l.(*defaultLogger); ok {
+		ll.logf(ctx, skip+1, lv, &format, v...)
+		return
+	}
+
+	switch lv {
+	case LevelTrace:
+		l.CtxTracef(ctx, format, v...)
+	case LevelDebug:
+		l.CtxDebugf(ctx, format, v...)
+	case LevelInfo:
+		l.CtxInfof(ctx, format, v...)
+	case LevelNotice:
+		l.CtxNoticef(ctx, format, v...)
+	case LevelWarn:
+		l.CtxWarnf(ctx, format, v...)
+	case LevelError:
+		l.CtxErrorf(ctx, format, v...)
+	case LevelFatal:
+		l.CtxFatalf(ctx, format, v...)
+	default:
+		l.CtxInfof(ctx, "%s%s", lv.toString(), fmt.Sprintf(format, v...))
+	}
+}
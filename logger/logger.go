@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // Logger 是一个记录器接口，提供带级别的日志记录功能。
@@ -78,9 +79,41 @@ var strs = []string{
 	"[Fatal] ",
 }
 
+var names = []string{
+	"trace",
+	"debug",
+	"info",
+	"notice",
+	"warn",
+	"error",
+	"fatal",
+}
+
 func (lv Level) toString() string {
 	if lv >= LevelTrace && lv <= LevelFatal {
 		return strs[lv]
 	}
 	return fmt.Sprintf("[?%d] ", lv)
 }
+
+// String 返回级别的小写名称，如 "info"。
+func (lv Level) String() string {
+	if lv >= LevelTrace && lv <= LevelFatal {
+		return names[lv]
+	}
+	return fmt.Sprintf("level(%d)", lv)
+}
+
+// ParseLevel 解析级别名称（不区分大小写），支持 "warning" 作为 "warn" 的别名。
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "warning" {
+		return LevelWarn, nil
+	}
+	for i, n := range names {
+		if n == name {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown level %q", s)
+}
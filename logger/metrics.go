@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMetrics 是 NewLogger 创建的记录器默认使用的统计。
+// 它实现了 expvar.Var，可通过 logger.DefaultMetrics.PublishExpvar("logger") 发布。
+var DefaultMetrics = NewMetrics()
+
+// levelCounters 按级别计数。
+type levelCounters [LevelFatal + 1]atomic.Uint64
+
+// Metrics 按级别和模块统计已输出的日志条数，以及被丢弃和被采样跳过的条数。
+// Metrics 实现了 expvar.Var 和 http.Handler，后者以 Prometheus 文本格式输出。
+type Metrics struct {
+	levels  levelCounters
+	modules sync.Map
+	dropped atomic.Uint64
+	sampled atomic.Uint64
+}
+
+// MetricsSnapshot 是 Metrics 在某一时刻的快照。
+type MetricsSnapshot struct {
+	Levels  map[string]uint64            `json:"levels"`
+	Modules map[string]map[string]uint64 `json:"modules"`
+	Dropped uint64                       `json:"dropped"`
+	Sampled uint64                       `json:"sampled"`
+}
+
+// NewMetrics 创建一个 Metrics。
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// metricsOf 返回记录器使用的统计，非本包创建的记录器使用 DefaultMetrics。
+func metricsOf(l FullLogger) *Metrics {
+	if ll, ok := l.(*defaultLogger); ok {
+		return ll.metrics
+	}
+	return DefaultMetrics
+}
+
+// observe 记录一条已输出的日志。
+func (m *Metrics) observe(module string, lv Level) {
+	if m == nil || lv < LevelTrace || lv > LevelFatal {
+		return
+	}
+	m.levels[lv].Add(1)
+
+	counters, ok := m.modules.Load(module)
+	if !ok {
+		counters, _ = m.modules.LoadOrStore(module, &levelCounters{})
+	}
+	counters.(*levelCounters)[lv].Add(1)
+}
+
+func (m *Metrics) addDropped() {
+	if m != nil {
+		m.dropped.Add(1)
+	}
+}
+
+func (m *Metrics) addSampled() {
+	if m != nil {
+		m.sampled.Add(1)
+	}
+}
+
+// Count 返回级别 lv 已输出的日志条数。
+func (m *Metrics) Count(lv Level) uint64 {
+	if m == nil || lv < LevelTrace || lv > LevelFatal {
+		return 0
+	}
+	return m.levels[lv].Load()
+}
+
+// ModuleCount 返回模块 module 中级别 lv 已输出的日志条数。
+func (m *Metrics) ModuleCount(module string, lv Level) uint64 {
+	if m == nil {
+		return 0
+	}
+	counters, ok := m.modules.Load(module)
+	if !ok || lv < LevelTrace || lv > LevelFatal {
+		return 0
+	}
+	return counters.(*levelCounters)[lv].Load()
+}
+
+// Dropped 返回因编码或写入失败而丢弃的日志条数，包括 LevelSplitWriter 没有对应文件的条目。
+func (m *Metrics) Dropped() uint64 {
+	if m == nil {
+		return 0
+	}
+	return m.dropped.Load()
+}
+
+// Sampled 返回被限流或采样跳过的日志条数。
+func (m *Metrics) Sampled() uint64 {
+	if m == nil {
+		return 0
+	}
+	return m.sampled.Load()
+}
+
+// Snapshot 返回当前统计的快照。m 为 nil 时各项计数均为 0。
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{
+		Levels:  make(map[string]uint64, len(names)),
+		Modules: map[string]map[string]uint64{},
+		Dropped: m.Dropped(),
+		Sampled: m.Sampled(),
+	}
+	for lv := LevelTrace; lv <= LevelFatal; lv++ {
+		s.Levels[lv.String()] = m.Count(lv)
+	}
+	if m == nil {
+		return s
+	}
+	m.modules.Range(func(key, value any) bool {
+		counts := make(map[string]uint64, len(names))
+		for lv := LevelTrace; lv <= LevelFatal; lv++ {
+			counts[lv.String()] = value.(*levelCounters)[lv].Load()
+		}
+		s.Modules[key.(string)] = counts
+		return true
+	})
+	return s
+}
+
+// String 以 JSON 格式返回统计，实现 expvar.Var 接口。
+func (m *Metrics) String() string {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// Expvar 返回以统计快照为值的 expvar.Func。
+func (m *Metrics) Expvar() expvar.Func {
+	return func() any { return m.Snapshot() }
+}
+
+// PublishExpvar 以 name 将统计发布到 expvar 并返回发布的变量。与 expvar.Publish 相同，name 已被发布时 panic。
+func (m *Metrics) PublishExpvar(name string) expvar.Func {
+	f := m.Expvar()
+	expvar.Publish(name, f)
+	return f
+}
+
+// ServeHTTP 以 Prometheus 文本格式输出统计。
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(m.Prometheus()))
+}
+
+// Prometheus 返回 Prometheus 文本格式的统计。
+func (m *Metrics) Prometheus() string {
+	s := m.Snapshot()
+
+	modules := make([]string, 0, len(s.Modules))
+	for module := range s.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	var sb strings.Builder
+	sb.WriteString("# HELP log_entries_total Number of log entries written, by level and module.\n")
+	sb.WriteString("# TYPE log_entries_total counter\n")
+	for _, module := range modules {
+		for lv := LevelTrace; lv <= LevelFatal; lv++ {
+			fmt.Fprintf(&sb, "log_entries_total{level=%q,module=\"%s\"} %d\n",
+				lv.String(), escapeLabel(module), s.Modules[module][lv.String()])
+		}
+	}
+	sb.WriteString("# HELP log_dropped_total Number of log entries dropped because they could not be encoded or written.\n")
+	sb.WriteString("# TYPE log_dropped_total counter\n")
+	fmt.Fprintf(&sb, "log_dropped_total %d\n", s.Dropped)
+	sb.WriteString("# HELP log_sampled_total Number of log entries skipped by sampling or throttling.\n")
+	sb.WriteString("# TYPE log_sampled_total counter\n")
+	fmt.Fprintf(&sb, "log_sampled_total %d\n", s.Sampled)
+	return sb.String()
+}
+
+// escapeLabel 按 Prometheus 文本格式转义标签值。
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
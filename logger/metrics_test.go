@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestMetrics_Counts(t *testing.T) {
+	m := NewMetrics()
+	root := NewLogger(WithOutput(io.Discard), WithMetrics(m), WithLevel(LevelInfo))
+	payment := Named(root, "payment")
+
+	root.Info("a")
+	root.Debug("filtered")
+	payment.Error("b")
+	payment.Errorf("c")
+
+	assert.Equal(t, uint64(1), m.Count(LevelInfo))
+	assert.Equal(t, uint64(0), m.Count(LevelDebug))
+	assert.Equal(t, uint64(2), m.Count(LevelError))
+	assert.Equal(t, uint64(2), m.ModuleCount("payment", LevelError))
+	assert.Equal(t, uint64(1), m.ModuleCount("", LevelInfo))
+
+	root.SetOutput(failingWriter{})
+	root.Info("lost")
+	assert.Equal(t, uint64(1), m.Dropped())
+	assert.Equal(t, uint64(1), m.Count(LevelInfo))
+}
+
+func TestMetrics_ModuleLevel(t *testing.T) {
+	m := NewMetrics()
+	root := NewLogger(WithOutput(io.Discard), WithMetrics(m), WithLevel(LevelInfo))
+	db := Named(root, "db")
+	db.SetLevel(LevelError)
+
+	db.Warn("hidden")
+	root.Warn("shown")
+	assert.Equal(t, uint64(0), m.ModuleCount("db", LevelWarn))
+	assert.Equal(t, uint64(1), m.ModuleCount("", LevelWarn))
+}
+
+func TestMetrics_Exposition(t *testing.T) {
+	m := NewMetrics()
+	l := NewLogger(WithOutput(io.Discard), WithMetrics(m), WithModule("pay\"ment"))
+	l.Warn("x")
+	m.addSampled()
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+	assert.Contains(t, body, "# TYPE log_entries_total counter\n")
+	assert.Contains(t, body, `log_entries_total{level="warn",module="pay\"ment"} 1`)
+	assert.Contains(t, body, "log_dropped_total 0\n")
+	assert.Contains(t, body, "log_sampled_total 1\n")
+
+	var v expvar.Var = m
+	var snapshot MetricsSnapshot
+	assert.NoError(t, json.Unmarshal([]byte(v.String()), &snapshot))
+	assert.Equal(t, uint64(1), snapshot.Levels["warn"])
+	assert.Equal(t, uint64(1), snapshot.Modules["pay\"ment"]["warn"])
+	assert.Equal(t, uint64(1), snapshot.Sampled)
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	l := NewLogger(WithOutput(io.Discard), WithMetrics(m))
+	l.Info("x")
+
+	assert.Equal(t, uint64(0), m.Count(LevelInfo))
+	assert.Equal(t, uint64(0), m.ModuleCount("", LevelInfo))
+	assert.Equal(t, uint64(0), m.Dropped())
+	assert.Equal(t, uint64(0), m.Sampled())
+	assert.Equal(t, uint64(0), m.Snapshot().Levels["info"])
+	assert.Contains(t, m.String(), `"dropped":0`)
+	assert.Contains(t, m.Prometheus(), "log_sampled_total 0\n")
+}
+
+func TestMetrics_PublishExpvar(t *testing.T) {
+	m := NewMetrics()
+	f := m.PublishExpvar("logger_test_metrics")
+	assert.NotNil(t, expvar.Get("logger_test_metrics"))
+
+	NewLogger(WithOutput(io.Discard), WithMetrics(m)).Error("x")
+	snapshot, ok := f.Value().(MetricsSnapshot)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), snapshot.Levels["error"])
+
+	var decoded MetricsSnapshot
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("logger_test_metrics").String()), &decoded))
+	assert.Equal(t, uint64(1), decoded.Levels["error"])
+}
@@ -0,0 +1,215 @@
+// Package middleware 提供基于 logger 的 net/http 中间件：panic 恢复和访问日志。
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/favbox/pkg/logger"
+)
+
+// DefaultRequestIDHeader 是读取和回写请求 ID 的默认请求头。
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDKey 是请求 ID 在日志字段中的键。
+const RequestIDKey = "request_id"
+
+type options struct {
+	logger          logger.FullLogger
+	requestIDHeader string
+}
+
+// Option 是中间件的配置项。
+type Option func(*options)
+
+// WithLogger 设置中间件使用的记录器，默认使用 logger.DefaultLogger()。
+func WithLogger(l logger.FullLogger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithRequestIDHeader 设置请求 ID 所在的请求头，默认为 X-Request-ID。
+func WithRequestIDHeader(header string) Option {
+	return func(o *options) {
+		o.requestIDHeader = header
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{requestIDHeader: DefaultRequestIDHeader}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// log 返回配置的记录器，未配置时使用当前的默认记录器。
+func (o *options) log() logger.FullLogger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return logger.DefaultLogger()
+}
+
+type requestIDKey struct{}
+
+// RequestIDFromContext 返回 AccessLog 或 RequestID 放入上下文的请求 ID。
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID 从请求头读取请求 ID，缺失时生成一个新的 ID 并写入响应头。
+// 请求 ID 会放入上下文，并作为日志字段出现在 CtxLogger 输出的日志中。
+func RequestID(next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, o.withRequestID(w, r))
+	})
+}
+
+func (o *options) withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	if RequestIDFromContext(r.Context()) != "" {
+		return r
+	}
+
+	id := r.Header.Get(o.requestIDHeader)
+	if id == "" {
+		id = logger.NewRequestID()
+	}
+	w.Header().Set(o.requestIDHeader, id)
+
+	ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+	ctx = logger.WithFields(ctx, logger.F(RequestIDKey, id))
+	return r.WithContext(ctx)
+}
+
+// responseWriter 记录响应状态码和写入的字节数。
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// flush 刷新底层的 ResponseWriter，调用方保证它实现了 http.Flusher。
+func (rw *responseWriter) flush() {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	rw.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack 接管底层的连接，调用方保证它实现了 http.Hijacker。
+// 接管连接后的响应由调用方写入，访问日志记录的状态码为 101。
+func (rw *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := rw.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil && rw.status == 0 {
+		rw.status = http.StatusSwitchingProtocols
+	}
+	return conn, brw, err
+}
+
+// Unwrap 供 http.ResponseController 访问底层的 ResponseWriter。
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *responseWriter) recorder() *responseWriter {
+	return rw
+}
+
+// wrappedWriter 由 wrapResponseWriter 返回的各种 ResponseWriter 实现，嵌套的中间件据此复用同一个 responseWriter。
+type wrappedWriter interface {
+	recorder() *responseWriter
+}
+
+// 以下类型只在底层的 ResponseWriter 实现了对应接口时使用，
+// 以免处理器通过类型断言误以为可以刷新或接管连接。
+type (
+	flushWriter       struct{ *responseWriter }
+	hijackWriter      struct{ *responseWriter }
+	flushHijackWriter struct{ *responseWriter }
+)
+
+func (w flushWriter) Flush()       { w.flush() }
+func (w flushHijackWriter) Flush() { w.flush() }
+
+func (w hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error)      { return w.hijack() }
+func (w flushHijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+// wrapResponseWriter 返回传给处理器的 ResponseWriter 以及记录状态码的 responseWriter。
+// 返回的 ResponseWriter 只实现 w 已实现的 http.Flusher 和 http.Hijacker。
+func wrapResponseWriter(w http.ResponseWriter) (http.ResponseWriter, *responseWriter) {
+	if r, ok := w.(wrappedWriter); ok {
+		return w, r.recorder()
+	}
+	rw := &responseWriter{ResponseWriter: w}
+	_, canFlush := w.(http.Flusher)
+	_, canHijack := w.(http.Hijacker)
+	switch {
+	case canFlush && canHijack:
+		return flushHijackWriter{rw}, rw
+	case canFlush:
+		return flushWriter{rw}, rw
+	case canHijack:
+		return hijackWriter{rw}, rw
+	default:
+		return rw, rw
+	}
+}
+
+// AccessLog 记录每个请求的方法、路径、状态码、响应字节数、耗时和请求 ID。
+// 5xx 响应以错误级别记录，4xx 以警告级别记录，其余以信息级别记录。
+// 与 Recovery 一起使用时应放在外层：AccessLog(Recovery(h))，以便记录 panic 产生的 500 响应。
+func AccessLog(next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r = o.withRequestID(w, r)
+		w, rw := wrapResponseWriter(w)
+
+		next.ServeHTTP(w, r)
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		ctx := logger.WithFields(r.Context(),
+			logger.F("method", r.Method),
+			logger.F("path", r.URL.Path),
+			logger.F("status", status),
+			logger.F("bytes", rw.bytes),
+			logger.F("latency", time.Since(start)),
+		)
+
+		l := o.log()
+		switch {
+		case status >= http.StatusInternalServerError:
+			l.CtxErrorf(ctx, "%s %s %d", r.Method, r.URL.Path, status)
+		case status >= http.StatusBadRequest:
+			l.CtxWarnf(ctx, "%s %s %d", r.Method, r.URL.Path, status)
+		default:
+			l.CtxInfof(ctx, "%s %s %d", r.Method, r.URL.Path, status)
+		}
+	})
+}
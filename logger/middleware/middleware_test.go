@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/favbox/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(buf *bytes.Buffer) logger.FullLogger {
+	return logger.NewLogger(logger.WithOutput(buf), logger.WithEncoder(&logger.JSONEncoder{}))
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m))
+		entries = append(entries, m)
+	}
+	return entries
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	h := AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", RequestIDFromContext(r.Context()))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}), WithLogger(newTestLogger(&buf)))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(DefaultRequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-1", rec.Header().Get(DefaultRequestIDHeader))
+	entries := decodeLines(t, &buf)
+	assert.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, "info", e["level"])
+	assert.Equal(t, "POST /orders 201", e["msg"])
+	assert.Equal(t, "POST", e["method"])
+	assert.Equal(t, "/orders", e["path"])
+	assert.Equal(t, float64(201), e["status"])
+	assert.Equal(t, float64(5), e["bytes"])
+	assert.Equal(t, "req-1", e[RequestIDKey])
+	assert.NotEmpty(t, e["latency"])
+}
+
+func TestAccessLog_GeneratesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	h := AccessLog(http.NotFoundHandler(), WithLogger(newTestLogger(&buf)))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	id := rec.Header().Get(DefaultRequestIDHeader)
+	assert.Len(t, id, 16)
+	e := decodeLines(t, &buf)[0]
+	assert.Equal(t, "warn", e["level"])
+	assert.Equal(t, id, e[RequestIDKey])
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+	h := AccessLog(Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), WithLogger(l)), WithLogger(l))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	entries := decodeLines(t, &buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "error", entries[0]["level"])
+	assert.Equal(t, "panic recovered: boom", entries[0]["msg"])
+	assert.Equal(t, "boom", entries[0]["panic"])
+	assert.Contains(t, entries[0]["stack"], "runtime/debug.Stack")
+	assert.NotEmpty(t, entries[0][RequestIDKey])
+	assert.Equal(t, "GET /panic 500", entries[1]["msg"])
+	assert.Equal(t, entries[0][RequestIDKey], entries[1][RequestIDKey])
+}
+
+func TestRecovery_AbortHandler(t *testing.T) {
+	h := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), WithLogger(newTestLogger(&bytes.Buffer{})))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+// hijackRecorder 是支持 http.Hijacker 的 ResponseRecorder。
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.conn, bufio.NewReadWriter(bufio.NewReader(r.conn), bufio.NewWriter(r.conn)), nil
+}
+
+func TestAccessLog_Hijack(t *testing.T) {
+	var buf bytes.Buffer
+	h := AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !assert.True(t, ok) {
+			return
+		}
+		conn, _, err := hj.Hijack()
+		assert.NoError(t, err)
+		assert.NoError(t, conn.Close())
+	}), WithLogger(newTestLogger(&buf)))
+
+	server, client := net.Pipe()
+	defer client.Close()
+	h.ServeHTTP(&hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server},
+		httptest.NewRequest(http.MethodGet, "/ws", nil))
+
+	entries := decodeLines(t, &buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, float64(101), entries[0]["status"])
+}
+
+func TestAccessLog_Flush(t *testing.T) {
+	var buf bytes.Buffer
+	h := AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := w.(http.Flusher)
+		assert.True(t, ok)
+		assert.NoError(t, http.NewResponseController(w).Flush())
+
+		_, ok = w.(http.Hijacker)
+		assert.False(t, ok)
+	}), WithLogger(newTestLogger(&buf)))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, float64(200), decodeLines(t, &buf)[0]["status"])
+}
+
+// plainWriter 只实现 http.ResponseWriter。
+type plainWriter struct {
+	http.ResponseWriter
+}
+
+func TestAccessLog_WriterInterfaces(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+	check := func(w http.ResponseWriter, flusher, hijacker bool) {
+		h := AccessLog(Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ok := w.(http.Flusher)
+			assert.Equal(t, flusher, ok)
+			_, ok = w.(http.Hijacker)
+			assert.Equal(t, hijacker, ok)
+			w.WriteHeader(http.StatusAccepted)
+		}), WithLogger(l)), WithLogger(l))
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	check(plainWriter{httptest.NewRecorder()}, false, false)
+	check(httptest.NewRecorder(), true, false)
+	check(&hijackRecorder{ResponseRecorder: httptest.NewRecorder()}, true, true)
+	check(struct {
+		http.ResponseWriter
+		http.Hijacker
+	}{plainWriter{httptest.NewRecorder()}, &hijackRecorder{}}, false, true)
+
+	for _, e := range decodeLines(t, &buf) {
+		assert.Equal(t, float64(http.StatusAccepted), e["status"])
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/favbox/pkg/logger"
+)
+
+// Recovery 恢复处理请求时发生的 panic，通过 CtxErrorf 记录 panic 值和调用栈，
+// 并在尚未写入响应头时返回 500。
+// http.ErrAbortHandler 会被重新抛出，以保持 net/http 中止响应的语义。
+func Recovery(next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w, rw := wrapResponseWriter(w)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			ctx := logger.WithFields(r.Context(),
+				logger.F("method", r.Method),
+				logger.F("path", r.URL.Path),
+				logger.F("panic", v),
+				logger.F("stack", string(debug.Stack())),
+			)
+			if err, ok := v.(error); ok {
+				ctx = logger.WithError(ctx, err)
+			}
+			o.log().CtxErrorf(ctx, "panic recovered: %v", v)
+
+			if rw.status == 0 {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
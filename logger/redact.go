@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactReplacement 是敏感内容被替换后的默认文本。
+const DefaultRedactReplacement = "***"
+
+// Redactor 在日志输出前遮盖敏感内容：键名匹配的字段值被整体替换，
+// 消息和字符串字段值中匹配正则表达式的部分被替换。
+type Redactor struct {
+	keys        map[string]struct{}
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// NewRedactor 创建 Redactor。keys 按不区分大小写的方式匹配字段名，
+// patterns 为正则表达式，replacement 为空时使用 DefaultRedactReplacement。
+func NewRedactor(keys []string, patterns []string, replacement string) (*Redactor, error) {
+	if replacement == "" {
+		replacement = DefaultRedactReplacement
+	}
+	r := &Redactor{
+		keys:        make(map[string]struct{}, len(keys)),
+		replacement: replacement,
+	}
+	for _, k := range keys {
+		r.keys[strings.ToLower(k)] = struct{}{}
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Message 替换消息中匹配的内容。
+func (r *Redactor) Message(msg string) string {
+	for _, re := range r.patterns {
+		msg = re.ReplaceAllString(msg, r.replacement)
+	}
+	return msg
+}
+
+// Fields 返回遮盖了敏感内容的字段副本，没有需要遮盖的内容时原样返回。
+func (r *Redactor) Fields(fields []Field) []Field {
+	var out []Field
+	for i, f := range fields {
+		redacted, changed := r.field(f)
+		if out == nil && changed {
+			out = make([]Field, len(fields))
+			copy(out, fields[:i])
+		}
+		if out != nil {
+			out[i] = redacted
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// field 返回遮盖后的字段，以及字段是否被修改。
+func (r *Redactor) field(f Field) (Field, bool) {
+	if _, ok := r.keys[strings.ToLower(f.Key)]; ok {
+		return Field{Key: f.Key, Value: r.replacement}, true
+	}
+	if d, ok := f.Value.(*ErrorDetail); ok && d != nil {
+		return Field{Key: f.Key, Value: r.errorDetail(d)}, true
+	}
+	if s, ok := f.Value.(string); ok && len(r.patterns) > 0 {
+		if redacted := r.Message(s); redacted != s {
+			return Field{Key: f.Key, Value: redacted}, true
+		}
+	}
+	return f, false
+}
+
+// errorDetail 返回遮盖了消息、调用栈和属性的错误副本，属性按字段的规则遮盖。
+func (r *Redactor) errorDetail(d *ErrorDetail) *ErrorDetail {
+	c := *d
+	c.Message = r.Message(d.Message)
+	c.Stack = r.Message(d.Stack)
+	if d.Attrs != nil {
+		c.Attrs = make(map[string]any, len(d.Attrs))
+		for k, v := range d.Attrs {
+			f, _ := r.field(Field{Key: k, Value: v})
+			c.Attrs[k] = f.Value
+		}
+	}
+	c.Causes = make([]*ErrorDetail, len(d.Causes))
+	for i, cause := range d.Causes {
+		c.Causes[i] = r.errorDetail(cause)
+	}
+	return &c
+}
+
+// WithRedactor 在输出前使用 r 遮盖敏感内容。
+func WithRedactor(r *Redactor) Option {
+	return func(l *defaultLogger) {
+		l.redactor = r
+	}
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// ReopenWriter 是写入指定路径文件的 io.WriteCloser。
+// 收到 SIGHUP 或调用 Reopen 时会重新打开该路径，用于配合 logrotate 的 create + HUP 模式。
+// 每次 Write 都在锁内完整写入，重新打开期间的并发写入不会丢失或交错。
+type ReopenWriter struct {
+	path string
+	perm os.FileMode
+
+	mu   sync.Mutex
+	file *os.File
+
+	sigs chan os.Signal
+	done chan struct{}
+	once sync.Once
+}
+
+// NewReopenWriter 以追加模式打开 path，并在收到 SIGHUP 时自动重新打开。
+// 可选的 perm 为新建文件的权限，默认 0644。
+func NewReopenWriter(path string, perm ...os.FileMode) (*ReopenWriter, error) {
+	w := &ReopenWriter{
+		path: path,
+		perm: 0o644,
+		sigs: make(chan os.Signal, 1),
+		done: make(chan struct{}),
+	}
+	if len(perm) > 0 {
+		w.perm = perm[0]
+	}
+
+	file, err := w.open()
+	if err != nil {
+		return nil, err
+	}
+	w.file = file
+
+	signal.Notify(w.sigs, syscall.SIGHUP)
+	go w.watch()
+
+	return w, nil
+}
+
+// Path 返回写入的文件路径。
+func (w *ReopenWriter) Path() string {
+	return w.path
+}
+
+// Write 将 p 写入当前文件。
+func (w *ReopenWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	return w.file.Write(p)
+}
+
+// Reopen 关闭当前文件并重新打开路径。
+// 新文件打开失败时继续写入旧文件并返回错误。
+func (w *ReopenWriter) Reopen() error {
+	file, err := w.open()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		_ = file.Close()
+		return os.ErrClosed
+	}
+	old := w.file
+	w.file = file
+	return old.Close()
+}
+
+// Close 停止监听信号并关闭文件。
+func (w *ReopenWriter) Close() error {
+	w.once.Do(func() {
+		signal.Stop(w.sigs)
+		close(w.done)
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *ReopenWriter) open() (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, w.perm)
+}
+
+func (w *ReopenWriter) watch() {
+	for {
+		select {
+		case <-w.sigs:
+			if err := w.Reopen(); err != nil && err != os.ErrClosed {
+				fmt.Fprintf(os.Stderr, "logger: reopen %s: %v\n", w.path, err)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readLines(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	content := strings.TrimSuffix(string(data), "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+func TestReopenWriter_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewReopenWriter(path)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, _ = w.Write([]byte("before\n"))
+	assert.NoError(t, os.Rename(path, path+".1"))
+	_, _ = w.Write([]byte("still old\n"))
+
+	assert.NoError(t, w.Reopen())
+	_, _ = w.Write([]byte("after\n"))
+
+	assert.Equal(t, []string{"before", "still old"}, readLines(t, path+".1"))
+	assert.Equal(t, []string{"after"}, readLines(t, path))
+}
+
+func TestReopenWriter_ConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewReopenWriter(path)
+	assert.NoError(t, err)
+
+	const writers, lines = 8, 200
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				_, _ = fmt.Fprintf(w, "writer-%d line-%d\n", i, j)
+			}
+		}(i)
+	}
+	for i := 1; i <= 5; i++ {
+		_ = os.Rename(path, fmt.Sprintf("%s.%d", path, i))
+		assert.NoError(t, w.Reopen())
+	}
+	wg.Wait()
+	assert.NoError(t, w.Close())
+
+	files, _ := filepath.Glob(path + "*")
+	total := 0
+	for _, f := range files {
+		for _, line := range readLines(t, f) {
+			assert.True(t, strings.HasPrefix(line, "writer-"), line)
+			total++
+		}
+	}
+	assert.Equal(t, writers*lines, total)
+}
+
+func TestReopenWriter_SIGHUP(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewReopenWriter(path)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, _ = w.Write([]byte("before\n"))
+	assert.NoError(t, os.Rename(path, path+".1"))
+	proc, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, proc.Signal(syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, _ = w.Write([]byte("after\n"))
+	assert.Equal(t, []string{"after"}, readLines(t, path))
+}
+
+func TestReopenWriter_Closed(t *testing.T) {
+	w, err := NewReopenWriter(filepath.Join(t.TempDir(), "app.log"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("x\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.ErrorIs(t, w.Reopen(), os.ErrClosed)
+}
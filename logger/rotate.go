@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotateTimeFormat 是轮转后文件名中的时间格式，按字典序排列即为时间顺序。
+const rotateTimeFormat = "20060102-150405.000"
+
+// RotateConfig 是文件轮转的配置，零值表示不轮转。
+type RotateConfig struct {
+	// MaxSize 是单个文件的最大字节数，写入后超过该大小时轮转，0 表示不按大小轮转。
+	MaxSize int64
+	// Interval 是按时间轮转的周期（如 24 * time.Hour），0 表示不按时间轮转。
+	Interval time.Duration
+	// MaxBackups 是保留的历史文件数量，0 表示全部保留。
+	MaxBackups int
+	// MaxAge 是历史文件的最长保留时间，0 表示不按时间清理。
+	MaxAge time.Duration
+}
+
+// RotateWriter 是按大小或时间轮转的文件 io.WriteCloser。
+// 轮转时当前文件被重命名为 "<path>.<时间>"，然后在原路径创建新文件。
+type RotateWriter struct {
+	path string
+	cfg  RotateConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotateWriter 以追加模式打开 path 并按 cfg 轮转。
+func NewRotateWriter(path string, cfg RotateConfig) (*RotateWriter, error) {
+	w := &RotateWriter{path: path, cfg: cfg}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Path 返回当前写入的文件路径。
+func (w *RotateWriter) Path() string {
+	return w.path
+}
+
+// Write 将 p 完整写入当前文件，必要时先轮转。
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	// 轮转失败但原文件已重新打开时继续写入，下次写入时再尝试轮转
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil && w.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate 立即轮转当前文件。
+func (w *RotateWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Close 关闭当前文件。
+func (w *RotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RotateWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.cfg.MaxSize > 0 && w.size+n > w.cfg.MaxSize {
+		return true
+	}
+	return w.cfg.Interval > 0 && time.Since(w.openedAt) >= w.cfg.Interval
+}
+
+func (w *RotateWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	if w.size > 0 {
+		w.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// rotate 关闭并重命名当前文件，然后在原路径打开新文件。
+// 关闭或重命名失败时仍以追加模式重新打开原路径，使后续日志可以继续写入。
+func (w *RotateWriter) rotate() error {
+	closeErr := w.file.Close()
+	w.file = nil
+
+	// 同一毫秒内多次轮转时顺延时间，避免覆盖已有的历史文件
+	t := time.Now()
+	backup := w.path + "." + t.Format(rotateTimeFormat)
+	for fileExists(backup) {
+		t = t.Add(time.Millisecond)
+		backup = w.path + "." + t.Format(rotateTimeFormat)
+	}
+	renameErr := os.Rename(w.path, backup)
+	if errors.Is(renameErr, os.ErrNotExist) {
+		renameErr = nil
+	}
+	if err := w.open(); err != nil {
+		return errors.Join(closeErr, renameErr, err)
+	}
+	if closeErr != nil || renameErr != nil {
+		return errors.Join(closeErr, renameErr)
+	}
+	w.prune()
+	return nil
+}
+
+// Backups 按时间从旧到新返回已轮转的历史文件。
+func (w *RotateWriter) Backups() ([]string, error) {
+	// 路径中可能含有 "["、"*" 等通配符，因此不使用 filepath.Glob
+	dir := filepath.Dir(w.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(w.path) + "."
+	var backups []string
+	for _, e := range entries {
+		suffix, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(rotateTimeFormat, suffix); err == nil {
+			backups = append(backups, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// prune 按 MaxBackups 和 MaxAge 清理历史文件。
+func (w *RotateWriter) prune() {
+	if w.cfg.MaxBackups <= 0 && w.cfg.MaxAge <= 0 {
+		return
+	}
+	backups, err := w.Backups()
+	if err != nil {
+		return
+	}
+
+	if w.cfg.MaxBackups > 0 && len(backups) > w.cfg.MaxBackups {
+		for _, b := range backups[:len(backups)-w.cfg.MaxBackups] {
+			_ = os.Remove(b)
+		}
+		backups = backups[len(backups)-w.cfg.MaxBackups:]
+	}
+	if w.cfg.MaxAge > 0 {
+		prefix := w.path + "."
+		deadline := time.Now().Add(-w.cfg.MaxAge)
+		for _, b := range backups {
+			t, _ := time.ParseInLocation(rotateTimeFormat, strings.TrimPrefix(b, prefix), time.Local)
+			if t.Before(deadline) {
+				_ = os.Remove(b)
+			}
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// maxSamplingKeys 限制每个周期内跟踪的消息数量，超出后新的消息不再采样。
+const maxSamplingKeys = 4096
+
+// SamplingConfig 是日志采样的配置。
+// 每个周期内，相同级别和消息的日志先输出 Initial 条，之后每 Thereafter 条输出一条。
+// 致命级别的日志不参与采样。
+type SamplingConfig struct {
+	// Tick 是采样周期，默认为 1 秒。
+	Tick time.Duration
+	// Initial 是每个周期内必定输出的条数。
+	Initial int
+	// Thereafter 是超出 Initial 后每多少条输出一条，0 表示全部丢弃。
+	Thereafter int
+}
+
+type samplingKey struct {
+	level   Level
+	message string
+}
+
+type sampler struct {
+	cfg SamplingConfig
+
+	mu      sync.Mutex
+	resetAt time.Time
+	counts  map[samplingKey]int
+}
+
+func newSampler(cfg SamplingConfig) *sampler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = time.Second
+	}
+	return &sampler{cfg: cfg, counts: map[samplingKey]int{}}
+}
+
+// allow 判断日志是否应该输出。
+func (s *sampler) allow(lv Level, msg string) bool {
+	if lv >= LevelFatal {
+		return true
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.After(s.resetAt) {
+		clear(s.counts)
+		s.resetAt = now.Add(s.cfg.Tick)
+	}
+
+	key := samplingKey{level: lv, message: msg}
+	n, ok := s.counts[key]
+	if !ok && len(s.counts) >= maxSamplingKeys {
+		return true
+	}
+	n++
+	s.counts[key] = n
+
+	if n <= s.cfg.Initial {
+		return true
+	}
+	return s.cfg.Thereafter > 0 && (n-s.cfg.Initial)%s.cfg.Thereafter == 0
+}
+
+// WithSampling 按 cfg 对日志采样，被跳过的条数计入 Metrics.Sampled。
+func WithSampling(cfg SamplingConfig) Option {
+	return func(l *defaultLogger) {
+		l.sampler = newSampler(cfg)
+	}
+}
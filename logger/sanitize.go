@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultTruncateMarker 是内容被截断时追加的默认标记。
+const DefaultTruncateMarker = "...(truncated)"
+
+// ansiPattern 匹配 CSI、OSC 以及其他以 ESC 开头的 ANSI 转义序列。
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]|\x{9b}[0-?]*[ -/]*[@-~]`)
+
+// Sanitizer 清理日志消息和字段值，防止用户输入伪造日志行或注入终端控制序列。
+// 零值会转义控制字符，不做截断。
+type Sanitizer struct {
+	// AllowControl 为 true 时保留控制字符，不进行转义。
+	AllowControl bool
+	// StripANSI 为 true 时移除 ANSI 转义序列，否则其中的 ESC 会被转义。
+	StripANSI bool
+	// MaxMessageSize 是消息转义后的最大字节数，0 表示不限制。
+	MaxMessageSize int
+	// MaxFieldSize 是单个字段值转义后的最大字节数，0 表示不限制。
+	MaxFieldSize int
+	// TruncateMarker 是截断后追加的标记，为空时使用 DefaultTruncateMarker。
+	TruncateMarker string
+}
+
+// Message 清理日志消息。
+func (s *Sanitizer) Message(msg string) string {
+	return s.clean(msg, s.MaxMessageSize, !s.AllowControl)
+}
+
+// Field 清理字段值。
+func (s *Sanitizer) Field(value string) string {
+	return s.clean(value, s.MaxFieldSize, !s.AllowControl)
+}
+
+// key 清理字段名，字段名不做截断。
+func (s *Sanitizer) key(k string) string {
+	return s.clean(k, 0, !s.AllowControl)
+}
+
+// clean 依次移除 ANSI 序列、转义控制字符并截断。截断按转义后的长度计算，不会拆分转义序列。
+func (s *Sanitizer) clean(v string, max int, escape bool) string {
+	if s.StripANSI {
+		v = StripANSI(v)
+	}
+	if escape && needsEscape(v) {
+		return escapeTruncate(v, max, s.marker(), escapeRune)
+	}
+	return Truncate(v, max, s.marker())
+}
+
+func (s *Sanitizer) marker() string {
+	if s.TruncateMarker == "" {
+		return DefaultTruncateMarker
+	}
+	return s.TruncateMarker
+}
+
+// StripANSI 移除 s 中的 ANSI 转义序列。
+func StripANSI(s string) string {
+	if !strings.ContainsAny(s, "\x1b\u009b") {
+		return s
+	}
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// Truncate 将 s 截断到不超过 max 字节（不拆分 UTF-8 字符），并追加 marker。
+func Truncate(s string, max int, marker string) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + marker
+}
+
+// EscapeControl 将 s 中的控制字符转义为可见形式，使一条日志始终只占一行。
+func EscapeControl(s string) string {
+	if !needsEscape(s) {
+		return s
+	}
+	return escapeTruncate(s, 0, "", escapeRune)
+}
+
+// escapeRune 返回 r 转义后的形式，不是控制字符时原样返回。
+func escapeRune(r rune) string {
+	switch {
+	case r == '\n':
+		return `\n`
+	case r == '\r':
+		return `\r`
+	case r == '\t':
+		return `\t`
+	case r < 0x20 || r == 0x7f:
+		return fmt.Sprintf(`\x%02x`, r)
+	case isUnicodeControl(r):
+		return fmt.Sprintf(`\u%04x`, r)
+	default:
+		return string(r)
+	}
+}
+
+// escapeTruncate 用 esc 逐个转义 s 中的字符。转义后超过 max 字节时在完整的转义序列处截断并追加 marker，
+// max <= 0 时不截断。
+func escapeTruncate(s string, max int, marker string, esc func(rune) string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 8)
+	for _, r := range s {
+		e := esc(r)
+		if max > 0 && sb.Len()+len(e) > max {
+			return sb.String() + marker
+		}
+		sb.WriteString(e)
+	}
+	return sb.String()
+}
+
+func needsEscape(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f || isUnicodeControl(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnicodeControl 判断 C1 控制字符以及行、段分隔符。
+func isUnicodeControl(r rune) bool {
+	return (r >= 0x80 && r <= 0x9f) || r == '\u2028' || r == '\u2029'
+}
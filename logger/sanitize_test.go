@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeControl(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "普通文本", input: "hello 世界", expected: "hello 世界"},
+		{name: "换行伪造", input: "ok\n[Error] forged", expected: `ok\n[Error] forged`},
+		{name: "回车与制表符", input: "a\r\tb", expected: `a\r\tb`},
+		{name: "ANSI 序列", input: "\x1b[31mred\x1b[0m", expected: `\x1b[31mred\x1b[0m`},
+		{name: "C1 与行分隔符", input: "a\u0085b\u2028c", expected: `a\u0085b\u2028c`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EscapeControl(tt.input))
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	assert.Equal(t, "red plain", StripANSI("\x1b[1;31mred\x1b[0m plain"))
+	assert.Equal(t, "title", StripANSI("\x1b]0;evil\x07title"))
+	assert.Equal(t, "no escapes", StripANSI("no escapes"))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "hello", Truncate("hello", 5, "..."))
+	assert.Equal(t, "hel...", Truncate("hello", 3, "..."))
+	// 不拆分多字节字符
+	assert.Equal(t, "世...", Truncate("世界", 4, "..."))
+	assert.Equal(t, "hello", Truncate("hello", 0, "..."))
+}
+
+func TestSanitizer(t *testing.T) {
+	s := &Sanitizer{
+		StripANSI:      true,
+		MaxMessageSize: 8,
+		MaxFieldSize:   4,
+		TruncateMarker: "~",
+	}
+	// 截断按转义后的长度计算
+	assert.Equal(t, `line\nne~`, s.Message("\x1b[31mline\nnext line"))
+	assert.Equal(t, `ab~`, s.Field("ab\x01cd"))
+	assert.Equal(t, "abcd~", s.Field("abcdef"))
+
+	var zero Sanitizer
+	assert.Equal(t, `a\nb`, zero.Message("a\nb"))
+
+	allow := Sanitizer{AllowControl: true}
+	assert.Equal(t, "a\nb", allow.Message("a\nb"))
+}
+
+func TestTextEncoder_QuotedFields(t *testing.T) {
+	enc := &TextEncoder{Order: []string{LayoutFields}}
+	encode := func(fields ...Field) string {
+		var buf bytes.Buffer
+		assert.NoError(t, enc.Encode(&buf, &Entry{Fields: fields}))
+		return strings.TrimSpace(buf.String())
+	}
+
+	assert.Equal(t, `path="C:\\dir\\"`, encode(F("path", `C:\dir\`)))
+	assert.Equal(t, `a="x\" b=y"`, encode(F("a", `x" b=y`)))
+	assert.Equal(t, `a="x\\" b=y`, encode(F("a", `x\`), F("b", "y")))
+	assert.Equal(t, `"k ey"=1 "k=ey"=2 plain=""`, encode(F("k ey", 1), F("k=ey", 2), F("plain", "")))
+	assert.Equal(t, `msg="a\nb\x1b"`, encode(F("msg", "a\nb\x1b")))
+
+	enc.Sanitizer = Sanitizer{MaxFieldSize: 5, TruncateMarker: "~"}
+	assert.Equal(t, `v="ab\nc~"`, encode(F("v", "ab\ncdef")))
+	assert.Equal(t, `v=abcde~`, encode(F("v", "abcdefg")))
+}
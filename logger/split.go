@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LevelWriter 由能够按级别写入的输出实现，记录器会优先调用 WriteLevel。
+type LevelWriter interface {
+	WriteLevel(lv Level, p []byte) (int, error)
+}
+
+// ErrBelowSplitLevels 表示条目的级别低于 LevelSplitWriter 配置的全部级别，条目未写入任何文件。
+var ErrBelowSplitLevels = errors.New("logger: level below all split levels")
+
+// LevelPlaceholder 是 SplitConfig.Pattern 中级别名称的占位符。
+const LevelPlaceholder = "{level}"
+
+// SplitConfig 是按级别拆分日志文件的配置。
+type SplitConfig struct {
+	// Dir 是日志文件所在目录。
+	Dir string
+	// Pattern 是文件名模板，{level} 会被替换为级别名称，默认为 "{level}.log"。
+	Pattern string
+	// Levels 是需要单独成文件的级别，默认为全部级别。
+	// 条目写入不高于其级别的最高一个文件，例如只配置 info、warn、error 时，
+	// notice 写入 info.log，fatal 写入 error.log，低于 info 的条目被丢弃，计入 Metrics.Dropped。
+	Levels []Level
+	// Cumulative 为 true 时，每个文件包含不低于其级别的全部条目，
+	// 例如 info.log 同时包含 warn 和 error 条目。
+	Cumulative bool
+	// Rotate 是每个文件的轮转配置。
+	Rotate RotateConfig
+}
+
+// LevelSplitWriter 按级别将日志写入不同的文件，每个文件独立轮转。
+// 它实现了 LevelWriter，作为 NewLogger 的输出使用。
+type LevelSplitWriter struct {
+	cfg     SplitConfig
+	levels  []Level
+	writers map[Level]*RotateWriter
+}
+
+// NewLevelSplitWriter 按 cfg 创建并打开各级别的日志文件。
+func NewLevelSplitWriter(cfg SplitConfig) (*LevelSplitWriter, error) {
+	if cfg.Pattern == "" {
+		cfg.Pattern = LevelPlaceholder + ".log"
+	}
+	if !strings.Contains(cfg.Pattern, LevelPlaceholder) {
+		return nil, fmt.Errorf("logger: split pattern %q must contain %s", cfg.Pattern, LevelPlaceholder)
+	}
+
+	levels := cfg.Levels
+	if len(levels) == 0 {
+		for lv := LevelTrace; lv <= LevelFatal; lv++ {
+			levels = append(levels, lv)
+		}
+	}
+	levels = append([]Level(nil), levels...)
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	w := &LevelSplitWriter{
+		cfg:     cfg,
+		writers: make(map[Level]*RotateWriter, len(levels)),
+	}
+	for _, lv := range levels {
+		if _, ok := w.writers[lv]; ok {
+			continue
+		}
+		path := filepath.Join(cfg.Dir, strings.ReplaceAll(cfg.Pattern, LevelPlaceholder, lv.String()))
+		rw, err := NewRotateWriter(path, cfg.Rotate)
+		if err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+		w.levels = append(w.levels, lv)
+		w.writers[lv] = rw
+	}
+	return w, nil
+}
+
+// Path 返回级别 lv 对应的文件路径，lv 没有单独的文件时返回空字符串。
+func (w *LevelSplitWriter) Path(lv Level) string {
+	if rw, ok := w.writers[lv]; ok {
+		return rw.Path()
+	}
+	return ""
+}
+
+// WriteLevel 按级别 lv 将 p 写入对应的文件，lv 低于全部配置的级别时返回 ErrBelowSplitLevels。
+func (w *LevelSplitWriter) WriteLevel(lv Level, p []byte) (int, error) {
+	if len(w.levels) > 0 && lv < w.levels[0] {
+		return 0, ErrBelowSplitLevels
+	}
+	var errs []error
+	for i := len(w.levels) - 1; i >= 0; i-- {
+		fileLevel := w.levels[i]
+		if fileLevel > lv {
+			continue
+		}
+		if _, err := w.writers[fileLevel].Write(p); err != nil {
+			errs = append(errs, err)
+		}
+		if !w.cfg.Cumulative {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	return len(p), nil
+}
+
+// Write 将不带级别的内容写入最低级别的文件。
+func (w *LevelSplitWriter) Write(p []byte) (int, error) {
+	if len(w.levels) == 0 {
+		return len(p), nil
+	}
+	return w.writers[w.levels[0]].Write(p)
+}
+
+// Close 关闭全部文件。
+func (w *LevelSplitWriter) Close() error {
+	var errs []error
+	for _, rw := range w.writers {
+		if err := rw.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateWriter_MaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotateWriter(path, RotateConfig{MaxSize: 10, MaxBackups: 2})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	for _, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		_, err = w.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+
+	backups, err := w.Backups()
+	assert.NoError(t, err)
+	assert.Len(t, backups, 2)
+	assert.Equal(t, []string{"dddddd"}, readLines(t, path))
+	assert.Equal(t, []string{"cccccc"}, readLines(t, backups[1]))
+	assert.Equal(t, []string{"bbbbbb"}, readLines(t, backups[0]))
+}
+
+func TestRotateWriter_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotateWriter(path, RotateConfig{})
+	assert.NoError(t, err)
+
+	_, _ = w.Write([]byte("one\n"))
+	assert.NoError(t, w.Rotate())
+	assert.NoError(t, w.Rotate())
+	_, _ = w.Write([]byte("two\n"))
+	assert.NoError(t, w.Close())
+
+	backups, _ := w.Backups()
+	assert.Len(t, backups, 2)
+	assert.Equal(t, []string{"two"}, readLines(t, path))
+	_, err = w.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRotateWriter_BackupsGlobChars(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs[1]*")
+	assert.NoError(t, os.Mkdir(dir, 0o755))
+	path := filepath.Join(dir, "app?[a].log")
+	w, err := NewRotateWriter(path, RotateConfig{})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app?[a].log.txt"), nil, 0o644))
+	assert.NoError(t, w.Rotate())
+	backups, err := w.Backups()
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+	assert.True(t, strings.HasPrefix(backups[0], path+"."))
+}
+
+func TestRotateWriter_RenameFails(t *testing.T) {
+	// 文件名加上时间后缀后超出长度限制，重命名必然失败
+	path := filepath.Join(t.TempDir(), strings.Repeat("a", 240))
+	w, err := NewRotateWriter(path, RotateConfig{MaxSize: 10})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("one\n"))
+	assert.NoError(t, err)
+	assert.Error(t, w.Rotate())
+
+	for _, line := range []string{"two\n", "three\n", "four\n"} {
+		n, err := w.Write([]byte(line))
+		assert.NoError(t, err)
+		assert.Equal(t, len(line), n)
+	}
+	assert.Equal(t, []string{"one", "two", "three", "four"}, readLines(t, path))
+}
+
+func TestLevelSplitWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewLevelSplitWriter(SplitConfig{
+		Dir:     dir,
+		Pattern: "app-{level}.log",
+		Levels:  []Level{LevelError, LevelInfo, LevelWarn},
+	})
+	assert.NoError(t, err)
+
+	m := NewMetrics()
+	l := NewLogger(WithOutput(w), WithCaller(false), WithMetrics(m), WithLevel(LevelTrace))
+	l.Debug("dropped")
+	l.Info("info")
+	l.Notice("notice")
+	l.Warn("warn")
+	l.Error("error")
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, filepath.Join(dir, "app-warn.log"), w.Path(LevelWarn))
+	assert.Equal(t, "", w.Path(LevelDebug))
+	assert.Equal(t, []string{"[Info] info", "[Notice] notice"}, messages(t, w.Path(LevelInfo)))
+	assert.Equal(t, []string{"[Warn] warn"}, messages(t, w.Path(LevelWarn)))
+	assert.Equal(t, []string{"[Error] error"}, messages(t, w.Path(LevelError)))
+	assert.Equal(t, uint64(1), m.Dropped())
+	assert.Equal(t, uint64(0), m.Count(LevelDebug))
+	_, err = w.WriteLevel(LevelDebug, []byte("x\n"))
+	assert.ErrorIs(t, err, ErrBelowSplitLevels)
+}
+
+func TestLevelSplitWriter_Cumulative(t *testing.T) {
+	w, err := NewLevelSplitWriter(SplitConfig{
+		Dir:        t.TempDir(),
+		Levels:     []Level{LevelInfo, LevelWarn, LevelError},
+		Cumulative: true,
+	})
+	assert.NoError(t, err)
+
+	l := NewLogger(WithOutput(w), WithCaller(false))
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"[Info] info", "[Warn] warn", "[Error] error"}, messages(t, w.Path(LevelInfo)))
+	assert.Equal(t, []string{"[Warn] warn", "[Error] error"}, messages(t, w.Path(LevelWarn)))
+	assert.Equal(t, []string{"[Error] error"}, messages(t, w.Path(LevelError)))
+}
+
+func TestLevelSplitWriter_Pattern(t *testing.T) {
+	_, err := NewLevelSplitWriter(SplitConfig{Dir: t.TempDir(), Pattern: "app.log"})
+	assert.Error(t, err)
+}
+
+// messages 去掉每行的时间前缀。
+func messages(t *testing.T, path string) []string {
+	var out []string
+	for _, line := range readLines(t, path) {
+		out = append(out, line[strings.Index(line, "["):])
+	}
+	return out
+}
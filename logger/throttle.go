@@ -0,0 +1,280 @@
+package logger
+
+import (
+	"container/list"
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultThrottleKeys 是包级限流函数最多跟踪的键数量。
+const DefaultThrottleKeys = 4096
+
+// Throttle 按键跟踪日志的出现次数与上次输出时间，用于实现“只记录一次”、
+// “每 N 次记录一次”和“每隔一段时间记录一次”。
+// 跟踪的键数量有上限，超过时淘汰最久未使用的键，被淘汰的键会重新开始计数。
+type Throttle struct {
+	mu      sync.Mutex
+	maxKeys int
+	lru     *list.List
+	items   map[string]*list.Element
+}
+
+type throttleItem struct {
+	key   string
+	count int
+	last  time.Time
+}
+
+// NewThrottle 创建最多跟踪 maxKeys 个键的 Throttle，maxKeys <= 0 时使用 DefaultThrottleKeys。
+func NewThrottle(maxKeys int) *Throttle {
+	if maxKeys <= 0 {
+		maxKeys = DefaultThrottleKeys
+	}
+	return &Throttle{
+		maxKeys: maxKeys,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// Once 仅在 key 第一次出现时返回 true。
+func (t *Throttle) Once(key string) bool {
+	return t.Every(key, 0)
+}
+
+// Every 在 key 第 1、n+1、2n+1…… 次出现时返回 true。n <= 0 时等同于 Once。
+func (t *Throttle) Every(key string, n int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item := t.touch(key)
+	item.count++
+	if n <= 0 {
+		return item.count == 1
+	}
+	return (item.count-1)%n == 0
+}
+
+// EveryInterval 在 key 第一次出现，或距上次返回 true 已超过 d 时返回 true。
+func (t *Throttle) EveryInterval(key string, d time.Duration) bool {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item := t.touch(key)
+	item.count++
+	if item.count == 1 || now.Sub(item.last) >= d {
+		item.last = now
+		return true
+	}
+	return false
+}
+
+// Forget 清除 key 的跟踪状态。
+func (t *Throttle) Forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if el, ok := t.items[key]; ok {
+		t.lru.Remove(el)
+		delete(t.items, key)
+	}
+}
+
+// Len 返回当前跟踪的键数量。
+func (t *Throttle) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lru.Len()
+}
+
+// touch 返回 key 对应的状态并将其标记为最近使用，必要时淘汰最久未使用的键。
+func (t *Throttle) touch(key string) *throttleItem {
+	if el, ok := t.items[key]; ok {
+		t.lru.MoveToFront(el)
+		return el.Value.(*throttleItem)
+	}
+
+	for t.lru.Len() >= t.maxKeys {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.items, oldest.Value.(*throttleItem).key)
+	}
+	item := &throttleItem{key: key}
+	t.items[key] = t.lru.PushFront(item)
+	return item
+}
+
+var throttle = NewThrottle(DefaultThrottleKeys)
+
+// pkgLimiter 返回包级限流函数使用的 Limiter，它输出到当前的默认记录器。
+func pkgLimiter() *Limiter {
+	return &Limiter{l: logger, t: throttle, skip: 1}
+}
+
+// LogOnce 以级别 lv 输出日志，相同 key 在进程内只输出一次。key 为空时以调用位置为键。
+func LogOnce(key string, lv Level, format string, v ...any) {
+	pkgLimiter().once(key, lv, format, v...)
+}
+
+// LogEvery 以级别 lv 输出日志，相同 key 每出现 n 次输出一次。key 为空时以调用位置为键。
+func LogEvery(key string, n int, lv Level, format string, v ...any) {
+	pkgLimiter().every(key, n, lv, format, v...)
+}
+
+// LogEveryInterval 以级别 lv 输出日志，相同 key 每隔 d 最多输出一次。key 为空时以调用位置为键。
+func LogEveryInterval(key string, d time.Duration, lv Level, format string, v ...any) {
+	pkgLimiter().everyInterval(key, d, lv, format, v...)
+}
+
+// InfoOnce 在同一调用位置只输出一次信息日志。
+func InfoOnce(format string, v ...any) {
+	pkgLimiter().once("", LevelInfo, format, v...)
+}
+
+// WarnOnce 在同一调用位置只输出一次警告日志，适用于弃用提示。
+func WarnOnce(format string, v ...any) {
+	pkgLimiter().once("", LevelWarn, format, v...)
+}
+
+// ErrorOnce 在同一调用位置只输出一次错误日志。
+func ErrorOnce(format string, v ...any) {
+	pkgLimiter().once("", LevelError, format, v...)
+}
+
+// InfoEvery 在同一调用位置每出现 n 次输出一次信息日志。
+func InfoEvery(n int, format string, v ...any) {
+	pkgLimiter().every("", n, LevelInfo, format, v...)
+}
+
+// WarnEvery 在同一调用位置每出现 n 次输出一次警告日志。
+func WarnEvery(n int, format string, v ...any) {
+	pkgLimiter().every("", n, LevelWarn, format, v...)
+}
+
+// ErrorEvery 在同一调用位置每出现 n 次输出一次错误日志。
+func ErrorEvery(n int, format string, v ...any) {
+	pkgLimiter().every("", n, LevelError, format, v...)
+}
+
+// InfoEveryInterval 在同一调用位置每隔 d 最多输出一次信息日志。
+func InfoEveryInterval(d time.Duration, format string, v ...any) {
+	pkgLimiter().everyInterval("", d, LevelInfo, format, v...)
+}
+
+// WarnEveryInterval 在同一调用位置每隔 d 最多输出一次警告日志，适用于重试等高频场景。
+func WarnEveryInterval(d time.Duration, format string, v ...any) {
+	pkgLimiter().everyInterval("", d, LevelWarn, format, v...)
+}
+
+// ErrorEveryInterval 在同一调用位置每隔 d 最多输出一次错误日志。
+func ErrorEveryInterval(d time.Duration, format string, v ...any) {
+	pkgLimiter().everyInterval("", d, LevelError, format, v...)
+}
+
+// Limiter 把限流输出写到指定的记录器，日志保留该记录器的模块名。
+// 每个 Limiter 使用自己的 Throttle 计数，与包级限流函数和其他 Limiter 互不影响。
+type Limiter struct {
+	l    FullLogger
+	t    *Throttle
+	skip int
+}
+
+// Limit 返回输出到 l 的 Limiter，最多跟踪 DefaultThrottleKeys 个键。
+func Limit(l FullLogger) *Limiter {
+	return &Limiter{l: l, t: NewThrottle(DefaultThrottleKeys), skip: 2}
+}
+
+// LogOnce 以级别 lv 输出日志，相同 key 只输出一次。key 为空时以调用位置为键。
+func (lm *Limiter) LogOnce(key string, lv Level, format string, v ...any) {
+	lm.once(key, lv, format, v...)
+}
+
+// LogEvery 以级别 lv 输出日志，相同 key 每出现 n 次输出一次。key 为空时以调用位置为键。
+func (lm *Limiter) LogEvery(key string, n int, lv Level, format string, v ...any) {
+	lm.every(key, n, lv, format, v...)
+}
+
+// LogEveryInterval 以级别 lv 输出日志，相同 key 每隔 d 最多输出一次。key 为空时以调用位置为键。
+func (lm *Limiter) LogEveryInterval(key string, d time.Duration, lv Level, format string, v ...any) {
+	lm.everyInterval(key, d, lv, format, v...)
+}
+
+// InfoOnce 在同一调用位置只输出一次信息日志。
+func (lm *Limiter) InfoOnce(format string, v ...any) {
+	lm.once("", LevelInfo, format, v...)
+}
+
+// WarnOnce 在同一调用位置只输出一次警告日志。
+func (lm *Limiter) WarnOnce(format string, v ...any) {
+	lm.once("", LevelWarn, format, v...)
+}
+
+// ErrorOnce 在同一调用位置只输出一次错误日志。
+func (lm *Limiter) ErrorOnce(format string, v ...any) {
+	lm.once("", LevelError, format, v...)
+}
+
+// InfoEvery 在同一调用位置每出现 n 次输出一次信息日志。
+func (lm *Limiter) InfoEvery(n int, format string, v ...any) {
+	lm.every("", n, LevelInfo, format, v...)
+}
+
+// WarnEvery 在同一调用位置每出现 n 次输出一次警告日志。
+func (lm *Limiter) WarnEvery(n int, format string, v ...any) {
+	lm.every("", n, LevelWarn, format, v...)
+}
+
+// ErrorEvery 在同一调用位置每出现 n 次输出一次错误日志。
+func (lm *Limiter) ErrorEvery(n int, format string, v ...any) {
+	lm.every("", n, LevelError, format, v...)
+}
+
+// InfoEveryInterval 在同一调用位置每隔 d 最多输出一次信息日志。
+func (lm *Limiter) InfoEveryInterval(d time.Duration, format string, v ...any) {
+	lm.everyInterval("", d, LevelInfo, format, v...)
+}
+
+// WarnEveryInterval 在同一调用位置每隔 d 最多输出一次警告日志。
+func (lm *Limiter) WarnEveryInterval(d time.Duration, format string, v ...any) {
+	lm.everyInterval("", d, LevelWarn, format, v...)
+}
+
+// ErrorEveryInterval 在同一调用位置每隔 d 最多输出一次错误日志。
+func (lm *Limiter) ErrorEveryInterval(d time.Duration, format string, v ...any) {
+	lm.everyInterval("", d, LevelError, format, v...)
+}
+
+// once、every 和 everyInterval 必须由导出的限流函数直接调用，以便 limit 计算调用位置。
+func (lm *Limiter) once(key string, lv Level, format string, v ...any) {
+	lm.limit(key, lm.t.Once, lv, format, v...)
+}
+
+func (lm *Limiter) every(key string, n int, lv Level, format string, v ...any) {
+	lm.limit(key, func(k string) bool { return lm.t.Every(k, n) }, lv, format, v...)
+}
+
+func (lm *Limiter) everyInterval(key string, d time.Duration, lv Level, format string, v ...any) {
+	lm.limit(key, func(k string) bool { return lm.t.EveryInterval(k, d) }, lv, format, v...)
+}
+
+// limit 在级别已启用且 allow 允许时输出日志。级别未启用的调用不消耗计数，也不计入采样统计。
+func (lm *Limiter) limit(key string, allow func(string) bool, lv Level, format string, v ...any) {
+	if ll, ok := lm.l.(*defaultLogger); ok && !ll.enabled(lv) {
+		return
+	}
+	if key == "" {
+		if _, file, line, ok := runtime.Caller(3); ok {
+			key = file + ":" + strconv.Itoa(line)
+		}
+	}
+	if allow(key) {
+		logAt(context.Background(), lm.l, lm.skip, lv, format, v...)
+	} else {
+		metricsOf(lm.l).addSampled()
+	}
+}
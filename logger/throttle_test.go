@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottle(t *testing.T) {
+	th := NewThrottle(10)
+
+	assert.True(t, th.Once("a"))
+	assert.False(t, th.Once("a"))
+	assert.True(t, th.Once("b"))
+
+	var hits []int
+	for i := 1; i <= 7; i++ {
+		if th.Every("every", 3) {
+			hits = append(hits, i)
+		}
+	}
+	assert.Equal(t, []int{1, 4, 7}, hits)
+
+	assert.True(t, th.EveryInterval("interval", 20*time.Millisecond))
+	assert.False(t, th.EveryInterval("interval", 20*time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, th.EveryInterval("interval", 20*time.Millisecond))
+
+	th.Forget("a")
+	assert.True(t, th.Once("a"))
+}
+
+func TestThrottle_Bounded(t *testing.T) {
+	th := NewThrottle(2)
+
+	assert.True(t, th.Once("a"))
+	assert.True(t, th.Once("b"))
+	assert.False(t, th.Once("a"))
+	// 淘汰最久未使用的 b
+	assert.True(t, th.Once("c"))
+	assert.Equal(t, 2, th.Len())
+	assert.False(t, th.Once("a"))
+	assert.True(t, th.Once("b"))
+}
+
+func TestLimitedHelpers(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(os.Stderr)
+
+	for i := 0; i < 5; i++ {
+		WarnOnce("deprecated %s", "api")
+		InfoEvery(2, "retry %d", i)
+		LogOnce("explicit", LevelError, "keyed %d", i)
+	}
+
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "[Warn] deprecated api"))
+	assert.Contains(t, out, "[Info] retry 0")
+	assert.Contains(t, out, "[Info] retry 2")
+	assert.Contains(t, out, "[Info] retry 4")
+	assert.NotContains(t, out, "[Info] retry 1")
+	assert.Equal(t, 1, strings.Count(out, "[Error] keyed 0"))
+	assert.Contains(t, out, "throttle_test.go:")
+	assert.NotContains(t, out, "throttle.go:")
+}
+
+func TestLimiter_Module(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf))
+	pay := Limit(Named(l, "payment"))
+	order := Limit(Named(l, "order"))
+
+	for i := 0; i < 3; i++ {
+		pay.LogOnce("shared", LevelWarn, "payment %d", i)
+		order.LogOnce("shared", LevelWarn, "order %d", i)
+		pay.InfoEvery(2, "tick %d", i)
+	}
+
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "[Warn] [payment] payment 0"))
+	assert.Equal(t, 1, strings.Count(out, "[Warn] [order] order 0"))
+	assert.Contains(t, out, "[Info] [payment] tick 0")
+	assert.Contains(t, out, "[Info] [payment] tick 2")
+	assert.NotContains(t, out, "tick 1")
+	assert.Contains(t, out, "throttle_test.go:")
+	assert.NotContains(t, out, "throttle.go:")
+}
+
+func TestLimiter_Independent(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	a := Limit(NewLogger(WithOutput(&buf1)))
+	b := Limit(NewLogger(WithOutput(&buf2)))
+	for i := 0; i < 2; i++ {
+		a.LogOnce("k", LevelInfo, "a %d", i)
+		b.LogOnce("k", LevelInfo, "b %d", i)
+	}
+	assert.Equal(t, 1, strings.Count(buf1.String(), "a 0"))
+	assert.Equal(t, 1, strings.Count(buf2.String(), "b 0"))
+}
+
+func TestLimiter_DisabledLevel(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMetrics()
+	l := NewLogger(WithOutput(&buf), WithMetrics(m), WithLevel(LevelWarn))
+	lm := Limit(l)
+
+	for i := 0; i < 3; i++ {
+		lm.LogOnce("k", LevelInfo, "hidden %d", i)
+	}
+	assert.Zero(t, m.Sampled())
+
+	l.SetLevel(LevelInfo)
+	lm.LogOnce("k", LevelInfo, "shown")
+	assert.Contains(t, buf.String(), "shown")
+	assert.NotContains(t, buf.String(), "hidden")
+}
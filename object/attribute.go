@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"github.com/samber/lo"
-	"strings"
 )
 
 type Attribute struct {
@@ -27,40 +26,30 @@ func (attr *Attribute) SetAttributes(attributes *HashMap) *Attribute {
 
 }
 
+// SetAttribute 使用"点"表示法设置属性，路径语法与 Collection.Set 相同，路径上缺少的 Map 创建为 map[string]any
 func (attr *Attribute) SetAttribute(name string, value any) *Attribute {
-	segments := strings.Split(name, ".")
-	newItem := attr.Attributes
-
-	var segment string
-	for len(segments) > 1 {
-		segment, segments = segments[0], segments[1:]
-		if newItem[segment] == nil {
-			newItem[segment] = map[string]any{}
-		}
-		newItem = newItem[segment].(map[string]any)
-	}
-
 	// transform the hashobject to string
-	switch value.(type) {
-	case map[string]any:
-	case HashMap:
-	case *HashMap:
+	if _, ok := value.(*HashMap); ok {
 		value, _ = JsonEncode(value)
-		break
-	default:
 	}
 
-	newItem[segments[0]] = value
+	if attr.Attributes == nil {
+		attr.Attributes = HashMap{}
+	}
+	// 与之前的版本一致，路径上缺少的 Map 创建为 map[string]any
+	if m, ok := setPathWith(attr.Attributes, parsePath(name), value, newStringAnyMapNode).(HashMap); ok {
+		attr.Attributes = m
+	}
 
 	return attr
 }
 
 func (attr *Attribute) GetRequired() []string {
-	if attr.Attributes["required"] != nil {
-		return attr.Attributes["required"].([]string)
-	} else {
+	required, err := ToStringSlice(attr.Attributes["required"])
+	if err != nil || required == nil {
 		return []string{}
 	}
+	return required
 }
 
 func (attr *Attribute) IsRequired(attribute string) bool {
@@ -73,53 +62,40 @@ func (attr *Attribute) GetAttributes() *HashMap {
 	return &attr.Attributes
 }
 
+// GetAttribute 获取属性，name 可以是顶层的键，也可以是"点"表示法的路径。
+// 不存在或为 nil 时返回 defaultValue，name 为空时返回全部属性
 func (attr *Attribute) GetAttribute(name string, defaultValue any) any {
-	var result any
-
-	hashedObject := attr.Attributes
-
 	if name == "" {
-		return &hashedObject
+		return &attr.Attributes
 	}
-
-	if hashedObject[name] != nil {
-		return hashedObject[name]
-	} else {
-		result = defaultValue
+	if v, ok := attr.lookup(name); ok {
+		return v
 	}
+	return defaultValue
+}
 
-	segments := strings.Split(name, ".")
-	if len(segments) > 1 {
-		for _, segment := range segments {
-			if hashedObject[segment] == nil {
-				return defaultValue
-			} else {
-				switch hashedObject[segment].(type) {
-				case HashMap:
-					hashedObject = hashedObject[segment].(HashMap)
-				case map[string]any:
-					hashedObject = hashedObject[segment].(map[string]any)
-				default:
-					return hashedObject[segment]
-				}
-			}
-		}
+// lookup 先按顶层的键、再按路径查找，值为 nil 时视为不存在
+func (attr *Attribute) lookup(name string) (any, bool) {
+	if v := attr.Attributes[name]; v != nil {
+		return v, true
 	}
-
-	return result
+	v, ok := getPath(attr.Attributes, parsePath(name))
+	return v, ok && v != nil
 }
 
 func (attr *Attribute) Get(attribute string, defaultValue any) any {
 	return attr.GetAttribute(attribute, defaultValue)
 }
 
+// Has 检查属性是否存在且不为 nil，key 可以是"点"表示法的路径
 func (attr *Attribute) Has(key string) bool {
-	return attr.Attributes[key] != nil
+	_, ok := attr.lookup(key)
+	return ok
 }
 
 func (attr *Attribute) GetString(attribute string, defaultValue string) string {
-	strResult := attr.Get(attribute, defaultValue).(string)
-	if strResult == "" {
+	strResult, err := ToString(attr.Get(attribute, defaultValue))
+	if err != nil || strResult == "" {
 		strResult = defaultValue
 	}
 	return strResult
@@ -26,3 +26,70 @@ func Test_Attribute_Set_AND_Get(t *testing.T) {
 	}
 
 }
+
+func Test_Attribute_NestedMapTypes(t *testing.T) {
+	var decoded HashMap
+	if err := JsonDecode([]byte(`{"weapon":{"bullet":100},"required":["weapon.bullet"]}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	attr := NewAttribute(&decoded)
+	attr.SetAttributes(&HashMap{
+		"weapon":   HashMap{"bullet": 100},
+		"armor":    &HashMap{"level": 2},
+		"labels":   StringMap{"team": "red"},
+		"required": decoded["required"],
+	})
+
+	// 中间节点是 HashMap 时不再 panic
+	attr.SetAttribute("weapon.shield.strength", "strong")
+	attr.SetAttribute("armor.type", "plate")
+	attr.SetAttribute("dotted.key", 1)
+
+	cases := map[string]any{
+		"weapon.bullet":          100,
+		"weapon.shield.strength": "strong",
+		"armor.level":            2,
+		"armor.type":             "plate",
+		"labels.team":            "red",
+		"dotted.key":             1,
+	}
+	for name, want := range cases {
+		if got := attr.Get(name, nil); got != want {
+			t.Errorf("Get(%q) = %v, want %v", name, got, want)
+		}
+		if !attr.Has(name) {
+			t.Errorf("Has(%q) = false", name)
+		}
+	}
+	if attr.Has("labels.missing") {
+		t.Error("Has(labels.missing) = true")
+	}
+	if got := attr.GetString("labels.team", ""); got != "red" {
+		t.Errorf("GetString = %q", got)
+	}
+	if err := attr.CheckRequiredAttributes(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Attribute_IntermediateMapType(t *testing.T) {
+	attr := NewAttribute(nil)
+	attr.SetAttribute("a.b.c", 1)
+	attr.SetAttribute("list[1].name", "x")
+
+	a, ok := attr.Attributes["a"].(map[string]any)
+	if !ok {
+		t.Fatalf("a is %T, want map[string]any", attr.Attributes["a"])
+	}
+	if _, ok := a["b"].(map[string]any); !ok {
+		t.Errorf("a.b is %T, want map[string]any", a["b"])
+	}
+	if list, ok := attr.Attributes["list"].([]any); !ok || len(list) != 2 {
+		t.Errorf("list is %#v", attr.Attributes["list"])
+	} else if _, ok := list[1].(map[string]any); !ok {
+		t.Errorf("list[1] is %T, want map[string]any", list[1])
+	}
+	if got := attr.GetAttribute("a.b.c", nil); got != 1 {
+		t.Errorf("GetAttribute(a.b.c) = %v", got)
+	}
+}
@@ -1,9 +1,5 @@
 package object
 
-import (
-	"strings"
-)
-
 // Collection 集合类型
 type Collection struct {
 	items *HashMap
@@ -24,82 +20,53 @@ func (c *Collection) All() *HashMap {
 	return c.items
 }
 
-// Get 使用"点"表示法从集合中获取项目
+// Get 使用"点"表示法从集合中获取项目。
+// 路径可以穿过任意键为 string 的 Map（HashMap、map[string]any、StringMap 等）和切片，下标写作 "items.0.sku" 或 "items[0].sku"，
+// 负数下标从末尾开始计算。按路径找不到时，key 作为顶层的键原样查找，因此 "a[0]" 这样的键仍然可以读取。
 func (c *Collection) Get(key string, defaultValue ...any) any {
 	if key == "" {
 		return GetDefaultValue(defaultValue...)
 	}
 
-	// 处理简单键
-	if !strings.Contains(key, ".") {
-		val := c.items.Get(key)
-		if IsZero(val) {
-			return GetDefaultValue(defaultValue...)
-		}
-		return val
+	val, ok := getPath(c.items, parsePath(key))
+	if !ok {
+		val, ok = (*c.items)[key]
 	}
-
-	// 处理嵌套键
-	segments := strings.Split(key, ".")
-	current := c.items
-
-	for _, segment := range segments[:len(segments)-1] {
-		val := current.Get(segment)
-		if IsZero(val) {
-			return GetDefaultValue(defaultValue...)
-		}
-
-		// 尝试将值转换为 Map
-		if nestedMap, ok := any(val).(*HashMap); ok {
-			current = nestedMap
-		} else {
-			return GetDefaultValue(defaultValue...)
-		}
-	}
-
-	val := current.Get(segments[len(segments)-1])
-	if IsZero(val) {
+	if !ok || IsZero(val) {
 		return GetDefaultValue(defaultValue...)
 	}
 	return val
 }
 
-// Set 设置集合中的值
-func (c *Collection) Set(key string, value any) {
+// Set 设置集合中的值，返回是否写入。
+// 路径中不存在或不是容器的部分会被替换为 *HashMap（"[n]" 形式的下标为 []any），
+// 切片长度不足时以零值扩展；下标超出末尾 1024 个及以上、负数下标超出范围或 key 为空时不写入，返回 false。
+func (c *Collection) Set(key string, value any) bool {
 	if key == "" {
-		return
+		return false
 	}
+	return c.setSegments(parsePath(key), value)
+}
 
-	segments := strings.Split(key, ".")
-	current := c.items
-
-	// 处理嵌套路径
-	for i := 0; i < len(segments)-1; i++ {
-		segment := segments[i]
-		val := current.Get(segment)
-
-		if IsZero(val) {
-			newMap := &HashMap{}
-			(*current)[segment] = any(newMap)
-			current = newMap
-			continue
-		}
-
-		if nestedMap, ok := any(val).(*HashMap); ok {
-			current = nestedMap
-		} else {
-			newMap := &HashMap{}
-			(*current)[segment] = any(newMap)
-			current = newMap
-		}
+// setSegments 按解析后的路径写入值，返回是否写入。根 Map 被替换（如 nil 的 Map）时写回 c.items
+func (c *Collection) setSegments(segments []pathSegment, value any) bool {
+	m, ok := setPath(c.items, segments, value).(*HashMap)
+	if ok && m != c.items {
+		*c.items = *m
 	}
-
-	(*current)[segments[len(segments)-1]] = value
+	return ok
 }
 
-// Has 检查键是否存在
+// Has 检查顶层的键或"点"表示法的路径是否存在
 func (c *Collection) Has(key string) bool {
-	return c.items.Has(key)
+	if c.items.Has(key) {
+		return true
+	}
+	if key == "" {
+		return false
+	}
+	_, ok := getPath(c.items, parsePath(key))
+	return ok
 }
 
 // Count 返回集合中的项目数
@@ -62,3 +62,105 @@ func Test_Collection_Set_AND_Get(t *testing.T) {
 		fmt.Println(shieldStrength)
 	}
 }
+
+func TestCollection_SliceIndex(t *testing.T) {
+	c := NewCollection(&HashMap{
+		"items": []any{
+			&HashMap{"sku": "A1", "qty": 1},
+			map[string]any{"sku": "B2", "tags": []string{"x", "y"}},
+		},
+		"rows":   []HashMap{{"id": 1}, {"id": 2}},
+		"matrix": [][]int{{1, 2}, {3, 4}},
+	})
+
+	assert.Equal(t, "A1", c.Get("items.0.sku"))
+	assert.Equal(t, "B2", c.Get("items[1].sku"))
+	assert.Equal(t, "B2", c.Get("items[-1].sku"))
+	assert.Equal(t, "y", c.Get("items[1].tags[-1]"))
+	assert.Equal(t, 2, c.Get("rows.1.id"))
+	assert.Equal(t, 4, c.Get("matrix[1][1]"))
+	assert.Equal(t, "none", c.Get("items[5].sku", "none"))
+	assert.Equal(t, "none", c.Get("items[-3].sku", "none"))
+	assert.Equal(t, "none", c.Get("items.x", "none"))
+
+	// 写入已有元素
+	c.Set("items[0].qty", 3)
+	c.Set("items.1.tags[0]", "z")
+	c.Set("rows[-1].id", 20)
+	c.Set("matrix[0][1]", 9)
+	assert.Equal(t, 3, c.Get("items.0.qty"))
+	assert.Equal(t, []string{"z", "y"}, c.Get("items.1.tags"))
+	assert.Equal(t, 20, c.Get("rows.1.id"))
+	assert.Equal(t, [][]int{{1, 9}, {3, 4}}, c.Get("matrix"))
+
+	// 扩展切片
+	c.Set("items[3].sku", "D4")
+	items := c.Get("items").([]any)
+	assert.Len(t, items, 4)
+	assert.Nil(t, items[2])
+	assert.Equal(t, "D4", c.Get("items.3.sku"))
+	c.Set("rows[2].id", 3)
+	assert.Equal(t, []HashMap{{"id": 1}, {"id": 20}, {"id": 3}}, c.Get("rows"))
+
+	// 元素类型无法容纳新值时转换为 []any
+	c.Set("matrix[2]", "row")
+	assert.Equal(t, []any{[]int{1, 9}, []int{3, 4}, "row"}, c.Get("matrix"))
+
+	// 超出范围的负数下标不写入
+	c.Set("items[-9].sku", "X")
+	assert.Len(t, c.Get("items").([]any), 4)
+
+	// 不存在的路径："[n]" 创建切片，".n" 创建 Map
+	c.Set("list[1]", "b")
+	assert.Equal(t, []any{nil, "b"}, c.Get("list"))
+	c.Set("years.2024.total", 10)
+	assert.Equal(t, &HashMap{"2024": &HashMap{"total": 10}}, c.Get("years"))
+}
+
+func TestCollection_SetEdgeCases(t *testing.T) {
+	// 指向 nil Map 的集合
+	c := NewCollection(new(HashMap))
+	c.Set("a", 1)
+	assert.Equal(t, 1, c.Get("a"))
+	c = NewCollection(new(HashMap))
+	c.Set("b.c", 2)
+	assert.Equal(t, 2, c.Get("b.c"))
+
+	// 过大的下标不写入，也不分配内存
+	c = NewCollection(&HashMap{"items": []any{1}})
+	assert.False(t, c.Set("items[99999999999]", 2))
+	assert.False(t, c.Set("items.99999999999", 2))
+	assert.False(t, c.Set("list[99999999999].x", 2))
+	assert.Equal(t, &HashMap{"items": []any{1}}, c.All())
+	assert.False(t, c.Set("items[1025]", 2))
+	assert.False(t, c.Set("items[-5]", 2))
+	assert.False(t, c.Set("", 2))
+	assert.Equal(t, 1, len(c.Get("items").([]any)))
+	assert.True(t, c.Set("items[1024]", 2))
+	assert.Equal(t, 1025, len(c.Get("items").([]any)))
+
+	// 数组被复制为切片
+	c = NewCollection(&HashMap{"arr": [2]int{1, 2}})
+	c.Set("arr[0]", 5)
+	assert.Equal(t, []int{5, 2}, c.Get("arr"))
+	c.Set("arr.2", 7)
+	assert.Equal(t, []int{5, 2, 7}, c.Get("arr"))
+}
+
+func TestCollection_LiteralBracketKey(t *testing.T) {
+	items := HashMap{"a[0]": "x", "b.c": "y", "a": []any{"z"}}
+	c := NewCollection(&items)
+	assert.Equal(t, "z", c.Get("a[0]"))
+	assert.Equal(t, "y", c.Get("b.c"))
+	assert.Equal(t, "y", items.GetString("b.c"))
+
+	c = NewCollection(&HashMap{"a[0]": "x"})
+	assert.Equal(t, "x", c.Get("a[0]"))
+	assert.True(t, c.Has("a[0]"))
+	assert.Equal(t, "x", NewSnapshot(c.All()).Get("a[0]"))
+
+	s := NewSafeCollection(new(HashMap))
+	assert.True(t, s.Set("k", 1))
+	assert.False(t, s.Set("items[5000]", 1))
+	assert.False(t, s.Has("items"))
+}
@@ -0,0 +1,333 @@
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound 表示路径在集合中不存在
+var ErrNotFound = errors.New("not found")
+
+// PathError 记录按路径读取值失败的原因
+type PathError struct {
+	// Path 是"点"表示法的路径
+	Path string
+	// Type 是实际值的类型，路径不存在时为空
+	Type string
+	// Want 是期望转换到的类型
+	Want string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	if errors.Is(e.Err, ErrNotFound) {
+		return fmt.Sprintf("object: %q not found", e.Path)
+	}
+	return fmt.Sprintf("object: %q is %s, cannot convert to %s: %v", e.Path, e.Type, e.Want, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+var errUnsupported = errors.New("unsupported type")
+
+// typeOf 返回值的类型名称，用于错误信息
+func typeOf(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	return reflect.TypeOf(v).String()
+}
+
+// ToString 将 v 转换为字符串，支持字符串、[]byte、json.Number、数字、布尔值和 fmt.Stringer
+func ToString(v any) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	}
+	return "", errUnsupported
+}
+
+// ToInt64 将 v 转换为 int64，支持整数、没有小数部分的浮点数、json.Number 和数字字符串
+func ToInt64(v any) (int64, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return parseInt64(val.String())
+	case string:
+		return parseInt64(val)
+	case []byte:
+		return parseInt64(string(val))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, strconv.ErrRange
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return floatToInt64(rv.Float())
+	case reflect.String:
+		return parseInt64(rv.String())
+	}
+	return 0, errUnsupported
+}
+
+func parseInt64(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return floatToInt64(f)
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%v has a fractional part", f)
+	}
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+	return int64(f), nil
+}
+
+// ToInt 将 v 转换为 int，规则与 ToInt64 相同
+func ToInt(v any) (int, error) {
+	n, err := ToInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(n)) != n {
+		return 0, strconv.ErrRange
+	}
+	return int(n), nil
+}
+
+// ToFloat64 将 v 转换为 float64，支持数字、json.Number 和数字字符串
+func ToFloat64(v any) (float64, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return val.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
+	case []byte:
+		return strconv.ParseFloat(strings.TrimSpace(string(val)), 64)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+	}
+	return 0, errUnsupported
+}
+
+// ToBool 将 v 转换为布尔值。
+// 字符串支持 "true"/"false"、"1"/"0"、"t"/"f"、"yes"/"no" 和 "on"/"off"（不区分大小写），数字非零为 true
+func ToBool(v any) (bool, error) {
+	switch val := v.(type) {
+	case bool:
+		return val, nil
+	case string:
+		return parseBool(val)
+	case []byte:
+		return parseBool(string(val))
+	case json.Number:
+		f, err := val.Float64()
+		return f != 0, err
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return !rv.IsZero(), nil
+	case reflect.String:
+		return parseBool(rv.String())
+	}
+	return false, errUnsupported
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// ToDuration 将 v 转换为 time.Duration。
+// 字符串按 time.ParseDuration 解析，数字和数字字符串表示秒数
+func ToDuration(v any) (time.Duration, error) {
+	switch val := v.(type) {
+	case time.Duration:
+		return val, nil
+	case string:
+		s := strings.TrimSpace(val)
+		if d, err := time.ParseDuration(s); err == nil {
+			return d, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", val)
+		}
+		return secondsToDuration(f)
+	}
+
+	f, err := ToFloat64(v)
+	if err != nil {
+		return 0, err
+	}
+	return secondsToDuration(f)
+}
+
+// secondsToDuration 将秒数转换为 time.Duration，超出范围时返回 strconv.ErrRange
+func secondsToDuration(f float64) (time.Duration, error) {
+	ns := f * float64(time.Second)
+	if math.IsNaN(ns) || ns < math.MinInt64 || ns >= math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+	return time.Duration(ns), nil
+}
+
+// timeLayouts 是 ToTime 支持的时间格式，没有时区的格式按本地时间解析
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ToTime 将 v 转换为 time.Time。
+// 字符串支持 RFC 3339、"2006-01-02 15:04:05" 和 "2006-01-02"，数字表示 Unix 秒数
+func ToTime(v any) (time.Time, error) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, nil
+	case *time.Time:
+		if val != nil {
+			return *val, nil
+		}
+	case string:
+		s := strings.TrimSpace(val)
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return unixTime(f), nil
+		}
+		return time.Time{}, fmt.Errorf("invalid time %q", val)
+	}
+
+	f, err := ToFloat64(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return unixTime(f), nil
+}
+
+func unixTime(sec float64) time.Time {
+	whole, frac := math.Modf(sec)
+	return time.Unix(int64(whole), int64(frac*1e9))
+}
+
+// ToStringSlice 将 v 转换为 []string，切片的每个元素按 ToString 转换，单个字符串视为只有一个元素的切片
+func ToStringSlice(v any) ([]string, error) {
+	switch val := v.(type) {
+	case []string:
+		return val, nil
+	case string:
+		return []string{val}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, errUnsupported
+	}
+	list := make([]string, rv.Len())
+	for i := range list {
+		s, err := ToString(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("element %d is %s: %w", i, typeOf(rv.Index(i).Interface()), err)
+		}
+		list[i] = s
+	}
+	return list, nil
+}
+
+// ToHashMap 将 v 转换为 *HashMap。
+// HashMap 和 map[string]any 与原值共享数据，其他键为字符串的 Map 会被复制
+func ToHashMap(v any) (*HashMap, error) {
+	switch val := v.(type) {
+	case *HashMap:
+		if val != nil {
+			return val, nil
+		}
+	case HashMap:
+		return &val, nil
+	case map[string]any:
+		m := HashMap(val)
+		return &m, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, errUnsupported
+	}
+	m := make(HashMap, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return &m, nil
+}
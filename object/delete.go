@@ -0,0 +1,217 @@
+package object
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Delete 使用"点"表示法删除集合中的项目，路径语法与 Get 相同。
+// 删除切片元素时，后面的元素会前移。不存在的路径被忽略。
+func (c *Collection) Delete(paths ...string) {
+	for _, path := range paths {
+		if path != "" {
+			deletePath(c.items, parsePath(path), false)
+		}
+	}
+}
+
+// Forget 是 Delete 的别名
+func (c *Collection) Forget(paths ...string) {
+	c.Delete(paths...)
+}
+
+// DeletePrune 与 Delete 相同，但会一并删除因此变为空的上级 Map 和切片
+func (c *Collection) DeletePrune(paths ...string) {
+	for _, path := range paths {
+		if path != "" {
+			deletePath(c.items, parsePath(path), true)
+		}
+	}
+}
+
+// Pull 获取并删除路径上的项目，路径不存在时返回 defaultValue
+func (c *Collection) Pull(path string, defaultValue ...any) any {
+	if path == "" {
+		return GetDefaultValue(defaultValue...)
+	}
+	segments := parsePath(path)
+	val, ok := getPath(c.items, segments)
+	if !ok {
+		return GetDefaultValue(defaultValue...)
+	}
+	deletePath(c.items, segments, false)
+	return val
+}
+
+// Only 返回只包含指定路径的新集合，不存在的路径被忽略。新集合中的值是复制的，不与原集合共享。
+// 原集合中的切片在新集合中仍是切片（[]any），无论路径写作 "items.1" 还是 "items[1]"，
+// 元素保持原来的下标，之前未选中的位置为 nil
+func (c *Collection) Only(paths ...string) *Collection {
+	result := NewCollection(nil)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		segments := parsePath(path)
+		if val, ok := getPath(c.items, segments); ok {
+			result.setSegments(sliceSegments(c.items, segments), cloneValue(val))
+		}
+	}
+	return result
+}
+
+// sliceSegments 返回 segments 的副本，其中在 root 中指向切片元素的段改写为非负的 [n] 形式，
+// 以便写入时创建切片而不是 Map。segments 必须是 root 中存在的路径
+func sliceSegments(root any, segments []pathSegment) []pathSegment {
+	out := slices.Clone(segments)
+	current := root
+	for i, seg := range segments {
+		if n, ok := asMapNode(current); ok {
+			current, _ = n.get(seg.key)
+			continue
+		}
+		rv := reflect.ValueOf(current)
+		j, _ := sliceIndex(seg.key, rv.Len())
+		out[i] = pathSegment{key: strconv.Itoa(j), bracket: true}
+		current = rv.Index(j).Interface()
+	}
+	return out
+}
+
+// Except 返回删除了指定路径的新集合。新集合中的值是复制的，不与原集合共享
+func (c *Collection) Except(paths ...string) *Collection {
+	items := cloneValue(c.items).(*HashMap)
+	result := NewCollection(items)
+	result.Delete(paths...)
+	return result
+}
+
+// deletePath 删除路径上的值，返回删除后的容器（切片可能变为新的切片）以及是否删除了值。
+// prune 为 true 时，子容器因删除变为空会从 current 中一并删除
+func deletePath(current any, segments []pathSegment, prune bool) (any, bool) {
+	seg, rest := segments[0], segments[1:]
+
+	if n, ok := asMapNode(current); ok {
+		if n.isNil() {
+			return current, false
+		}
+		child, exists := n.get(seg.key)
+		if !exists {
+			return current, false
+		}
+		if len(rest) == 0 {
+			n.delete(seg.key)
+			return current, true
+		}
+		updated, removed := deletePath(child, rest, prune)
+		if !removed {
+			return current, false
+		}
+		if prune && isEmptyContainer(updated) {
+			n.delete(seg.key)
+			return current, true
+		}
+		return setMapKey(current, n, seg.key, updated), true
+	}
+
+	rv := reflect.ValueOf(current)
+	if !rv.IsValid() || rv.Kind() != reflect.Slice {
+		return current, false
+	}
+	i, ok := sliceIndex(seg.key, rv.Len())
+	if !ok || i >= rv.Len() {
+		return current, false
+	}
+	if len(rest) > 0 {
+		updated, removed := deletePath(rv.Index(i).Interface(), rest, prune)
+		if !removed {
+			return current, false
+		}
+		if !prune || !isEmptyContainer(updated) {
+			setSliceElem(rv, i, updated)
+			return current, true
+		}
+	}
+
+	// 删除第 i 个元素，返回新的切片，避免修改共享的底层数组
+	result := reflect.MakeSlice(rv.Type(), 0, rv.Len()-1)
+	result = reflect.AppendSlice(result, rv.Slice(0, i))
+	result = reflect.AppendSlice(result, rv.Slice(i+1, rv.Len()))
+	return result.Interface(), true
+}
+
+// setSliceElem 在类型允许时写回切片元素
+func setSliceElem(rv reflect.Value, i int, v any) {
+	ev := reflect.ValueOf(v)
+	if ev.IsValid() && ev.Type().AssignableTo(rv.Type().Elem()) {
+		rv.Index(i).Set(ev)
+	}
+}
+
+// isEmptyContainer 判断 v 是否为空的 Map 或切片
+func isEmptyContainer(v any) bool {
+	if n, ok := asMapNode(v); ok {
+		return n.len() == 0
+	}
+	rv := reflect.ValueOf(v)
+	return rv.IsValid() && rv.Kind() == reflect.Slice && rv.Len() == 0
+}
+
+// cloneValue 深度复制 v 中的 Map 和切片，保持原有的类型，其他值原样返回
+func cloneValue(v any) any {
+	return cloneWith(v, true)
+}
+
+// cloneWith 深度复制 v 中的 Map，withSlices 为 true 时也复制切片
+func cloneWith(v any, withSlices bool) any {
+	if n, ok := asMapNode(v); ok {
+		if n.isNil() {
+			return v
+		}
+		if n.m != nil {
+			dst := make(map[string]any, len(n.m))
+			for k, val := range n.m {
+				dst[k] = cloneWith(val, withSlices)
+			}
+			switch v.(type) {
+			case HashMap:
+				return HashMap(dst)
+			case *HashMap:
+				hm := HashMap(dst)
+				return &hm
+			}
+			return dst
+		}
+
+		dst := reflect.MakeMapWithSize(n.rv.Type(), n.len())
+		iter := n.rv.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), cloneElem(iter.Value(), withSlices))
+		}
+		if reflect.ValueOf(v).Kind() == reflect.Pointer {
+			ptr := reflect.New(n.rv.Type())
+			ptr.Elem().Set(dst)
+			return ptr.Interface()
+		}
+		return dst.Interface()
+	}
+
+	rv := reflect.ValueOf(v)
+	if !withSlices || !rv.IsValid() || rv.Kind() != reflect.Slice || rv.IsNil() {
+		return v
+	}
+	dst := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		dst.Index(i).Set(cloneElem(rv.Index(i), withSlices))
+	}
+	return dst.Interface()
+}
+
+// cloneElem 复制 Map 或切片中的元素，复制结果无法放回原类型时使用原值
+func cloneElem(elem reflect.Value, withSlices bool) reflect.Value {
+	if c := reflect.ValueOf(cloneWith(elem.Interface(), withSlices)); c.IsValid() && c.Type().AssignableTo(elem.Type()) {
+		return c
+	}
+	return elem
+}
@@ -0,0 +1,108 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderCollection() *Collection {
+	return NewCollection(&HashMap{
+		"id": 1,
+		"customer": &HashMap{
+			"name":    "alice",
+			"email":   "alice@example.com",
+			"address": map[string]any{"city": "shanghai"},
+		},
+		"items": []any{
+			&HashMap{"sku": "A1", "qty": 1},
+			&HashMap{"sku": "B2", "qty": 2},
+			&HashMap{"sku": "C3", "qty": 3},
+		},
+		"tags": []string{"new", "vip"},
+	})
+}
+
+func TestCollection_Delete(t *testing.T) {
+	c := newOrderCollection()
+	c.Delete("customer.email", "items[1]", "tags.-1", "missing.path", "items[9]")
+
+	assert.False(t, c.Has("missing"))
+	assert.Equal(t, "none", c.Get("customer.email", "none"))
+	assert.Equal(t, "alice", c.Get("customer.name"))
+	assert.Len(t, c.Get("items"), 2)
+	assert.Equal(t, "C3", c.Get("items[1].sku"))
+	assert.Equal(t, []string{"new"}, c.Get("tags"))
+
+	c.Forget("id", "items.0.qty")
+	assert.False(t, c.Has("id"))
+	assert.Equal(t, &HashMap{"sku": "A1"}, c.Get("items.0"))
+}
+
+func TestCollection_DeletePrune(t *testing.T) {
+	c := newOrderCollection()
+	c.Delete("customer.address.city")
+	assert.Equal(t, map[string]any{}, (*c.Get("customer").(*HashMap))["address"])
+
+	c = newOrderCollection()
+	c.DeletePrune("customer.address.city")
+	assert.Equal(t, &HashMap{"name": "alice", "email": "alice@example.com"}, c.Get("customer"))
+
+	c.DeletePrune("customer.name", "customer.email")
+	assert.False(t, c.Has("customer"))
+
+	c.DeletePrune("tags[0]", "tags[0]")
+	assert.False(t, c.Has("tags"))
+
+	c.DeletePrune("items[0].sku", "items[0].qty")
+	assert.Len(t, c.Get("items"), 2)
+	assert.Equal(t, "B2", c.Get("items[0].sku"))
+}
+
+func TestCollection_Pull(t *testing.T) {
+	c := newOrderCollection()
+	assert.Equal(t, "B2", c.Pull("items[1].sku"))
+	assert.Equal(t, &HashMap{"qty": 2}, c.Get("items[1]"))
+	assert.Equal(t, &HashMap{"sku": "A1", "qty": 1}, c.Pull("items.0"))
+	assert.Len(t, c.Get("items"), 2)
+	assert.Equal(t, "none", c.Pull("customer.phone", "none"))
+}
+
+func TestCollection_OnlyExcept(t *testing.T) {
+	c := newOrderCollection()
+
+	only := c.Only("id", "customer.name", "items[0].sku", "missing")
+	assert.Equal(t, &HashMap{
+		"id":       1,
+		"customer": &HashMap{"name": "alice"},
+		"items":    []any{&HashMap{"sku": "A1"}},
+	}, only.All())
+
+	// 无论下标写作 ".1"、"[1]" 还是 "[-2]"，切片都保持为切片，元素保持原来的下标
+	want := &HashMap{"items": []any{nil, &HashMap{"sku": "B2"}}}
+	for _, path := range []string{"items.1.sku", "items[1].sku", "items[-2].sku"} {
+		assert.Equal(t, want, c.Only(path).All(), path)
+	}
+	assert.Equal(t, &HashMap{"items": []any{&HashMap{"qty": 1}, nil, &HashMap{"sku": "C3"}}},
+		c.Only("items.2.sku", "items.0.qty").All())
+
+	except := c.Except("customer.email", "items[0]", "tags")
+	assert.Equal(t, &HashMap{
+		"id": 1,
+		"customer": &HashMap{
+			"name":    "alice",
+			"address": map[string]any{"city": "shanghai"},
+		},
+		"items": []any{
+			&HashMap{"sku": "B2", "qty": 2},
+			&HashMap{"sku": "C3", "qty": 3},
+		},
+	}, except.All())
+
+	// 新集合不与原集合共享数据
+	except.Set("items[0].sku", "X")
+	only.Set("customer.name", "bob")
+	assert.Equal(t, "B2", c.Get("items[1].sku"))
+	assert.Equal(t, "alice", c.Get("customer.name"))
+	assert.Equal(t, "alice@example.com", c.Get("customer.email"))
+}
@@ -0,0 +1,300 @@
+package object
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ChangeType 是 Diff 中变更的类型
+type ChangeType string
+
+const (
+	// ChangeAdded 表示路径只存在于新值中
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved 表示路径只存在于旧值中
+	ChangeRemoved ChangeType = "removed"
+	// ChangeModified 表示路径上的值发生了变化
+	ChangeModified ChangeType = "modified"
+)
+
+// Change 是 Diff 发现的一处变更
+type Change struct {
+	Type ChangeType `json:"type"`
+	// Path 是"点"表示法的路径，切片下标作为普通的一段，如 "items.0.sku"
+	Path string `json:"path"`
+	// Old 是旧值，ChangeAdded 时为 nil
+	Old any `json:"old"`
+	// New 是新值，ChangeRemoved 时为 nil
+	New any `json:"new"`
+}
+
+// MarshalJSON 按变更类型输出 old 和 new：ChangeAdded 不含 old，ChangeRemoved 不含 new，
+// 其余情况总是同时包含两者，因此值为 null 与字段不存在可以区分
+func (c Change) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Type ChangeType `json:"type"`
+		Path string     `json:"path"`
+		Old  *any       `json:"old,omitempty"`
+		New  *any       `json:"new,omitempty"`
+	}{Type: c.Type, Path: c.Path}
+	if c.Type != ChangeAdded {
+		out.Old = &c.Old
+	}
+	if c.Type != ChangeRemoved {
+		out.New = &c.New
+	}
+	return json.Marshal(out)
+}
+
+// DiffOptions 是 Diff 的选项，nil 表示使用默认值
+type DiffOptions struct {
+	// IgnorePaths 中的路径及其子路径不参与比较，"*" 匹配任意一段，如 "items.*.updatedAt"
+	IgnorePaths []string
+	// NumericEqual 为 true 时数值只比较大小，int 1 与 float64 1 相等
+	NumericEqual bool
+	// IgnoreSliceOrder 为 true 时切片按多重集合比较，元素顺序不同不视为变更
+	IgnoreSliceOrder bool
+}
+
+// Diff 比较 a 和 b，返回从 a 到 b 的变更列表。
+// 两边都是 Map（任意键为 string 的 Map，不区分具体类型）时逐键递归比较，
+// 两边都是切片时逐个下标递归比较，多出的元素记为 ChangeAdded 或 ChangeRemoved；
+// 其他值不同（reflect.DeepEqual）时记为 ChangeModified。
+// 变更按路径的遍历顺序排列（Map 按键的字典序，切片按下标），结果是确定的。
+func Diff(a, b *HashMap, opts *DiffOptions) []Change {
+	d := newDiffer(opts)
+	var changes []Change
+	d.diffMap(nil, mapNodeOf(a), mapNodeOf(b), func(t ChangeType, segments []string, old, new any) bool {
+		changes = append(changes, Change{Type: t, Path: strings.Join(segments, "."), Old: old, New: new})
+		return true
+	})
+	return changes
+}
+
+func mapNodeOf(m *HashMap) mapNode {
+	if m == nil {
+		return mapNode{m: map[string]any{}}
+	}
+	return mapNode{m: *m}
+}
+
+// diffEmit 接收一处变更，segments 为路径的各段，返回 false 时停止比较
+type diffEmit func(t ChangeType, segments []string, old, new any) bool
+
+type differ struct {
+	opts   DiffOptions
+	ignore [][]string
+}
+
+func newDiffer(opts *DiffOptions) *differ {
+	d := &differ{}
+	if opts != nil {
+		d.opts = *opts
+	}
+	for _, p := range d.opts.IgnorePaths {
+		var segments []string
+		for _, seg := range parsePath(p) {
+			segments = append(segments, seg.key)
+		}
+		d.ignore = append(d.ignore, segments)
+	}
+	return d
+}
+
+// ignored 判断路径是否匹配 IgnorePaths 中的某个路径或其子路径
+func (d *differ) ignored(segments []string) bool {
+	for _, pattern := range d.ignore {
+		if len(pattern) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, p := range pattern {
+			if p != "*" && p != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// diff 比较路径上的两个值，对每处变更调用 emit，emit 返回 false 时停止比较并返回 false
+func (d *differ) diff(segments []string, a, b any, emit diffEmit) bool {
+	if d.ignored(segments) {
+		return true
+	}
+
+	an, aIsMap := asMapNode(a)
+	bn, bIsMap := asMapNode(b)
+	if aIsMap && bIsMap {
+		return d.diffMap(segments, an, bn, emit)
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if isSliceValue(av) && isSliceValue(bv) {
+		if d.opts.IgnoreSliceOrder {
+			return d.diffUnordered(segments, av, bv, emit)
+		}
+		return d.diffSlice(segments, av, bv, emit)
+	}
+
+	if d.equalLeaf(a, b) {
+		return true
+	}
+	return emit(ChangeModified, segments, a, b)
+}
+
+func (d *differ) diffMap(segments []string, a, b mapNode, emit diffEmit) bool {
+	keys := a.keys()
+	for _, k := range b.keys() {
+		if _, ok := a.get(k); !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		path := appendSegment(segments, k)
+		av, inA := a.get(k)
+		bv, inB := b.get(k)
+		var ok bool
+		switch {
+		case !inB:
+			ok = d.ignored(path) || emit(ChangeRemoved, path, av, nil)
+		case !inA:
+			ok = d.ignored(path) || emit(ChangeAdded, path, nil, bv)
+		default:
+			ok = d.diff(path, av, bv, emit)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *differ) diffSlice(segments []string, a, b reflect.Value, emit diffEmit) bool {
+	for i := 0; i < max(a.Len(), b.Len()); i++ {
+		path := appendSegment(segments, strconv.Itoa(i))
+		var ok bool
+		switch {
+		case i >= b.Len():
+			ok = d.ignored(path) || emit(ChangeRemoved, path, a.Index(i).Interface(), nil)
+		case i >= a.Len():
+			ok = d.ignored(path) || emit(ChangeAdded, path, nil, b.Index(i).Interface())
+		default:
+			ok = d.diff(path, a.Index(i).Interface(), b.Index(i).Interface(), emit)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// diffUnordered 将 a 的每个元素与 b 中第一个未配对的相等元素配对，
+// 未配对的元素按各自的下标记为 ChangeRemoved 或 ChangeAdded
+func (d *differ) diffUnordered(segments []string, a, b reflect.Value, emit diffEmit) bool {
+	matched := make([]bool, b.Len())
+	var removed []int
+	for i := 0; i < a.Len(); i++ {
+		path := appendSegment(segments, strconv.Itoa(i))
+		found := false
+		for j := 0; j < b.Len(); j++ {
+			if !matched[j] && d.equal(path, a.Index(i).Interface(), b.Index(j).Interface()) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, i)
+		}
+	}
+
+	for _, i := range removed {
+		path := appendSegment(segments, strconv.Itoa(i))
+		if !d.ignored(path) && !emit(ChangeRemoved, path, a.Index(i).Interface(), nil) {
+			return false
+		}
+	}
+	for j := 0; j < b.Len(); j++ {
+		path := appendSegment(segments, strconv.Itoa(j))
+		if !matched[j] && !d.ignored(path) && !emit(ChangeAdded, path, nil, b.Index(j).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal 判断两个值在当前选项下是否没有差异
+func (d *differ) equal(segments []string, a, b any) bool {
+	return d.diff(segments, a, b, func(ChangeType, []string, any, any) bool { return false })
+}
+
+func (d *differ) equalLeaf(a, b any) bool {
+	if d.opts.NumericEqual {
+		if equal, ok := numericEqual(a, b); ok {
+			return equal
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numericEqual 比较两个数值的大小，ok 为 false 表示至少一个不是数值
+func numericEqual(a, b any) (equal, ok bool) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	ak, bk := numberKind(av), numberKind(bv)
+	if ak == reflect.Invalid || bk == reflect.Invalid {
+		return false, false
+	}
+
+	switch {
+	case ak == reflect.Int && bk == reflect.Int:
+		return av.Int() == bv.Int(), true
+	case ak == reflect.Uint && bk == reflect.Uint:
+		return av.Uint() == bv.Uint(), true
+	case ak == reflect.Int && bk == reflect.Uint:
+		return av.Int() >= 0 && uint64(av.Int()) == bv.Uint(), true
+	case ak == reflect.Uint && bk == reflect.Int:
+		return bv.Int() >= 0 && av.Uint() == uint64(bv.Int()), true
+	}
+	return toFloat(av) == toFloat(bv), true
+}
+
+// numberKind 将数值类型归为 reflect.Int、reflect.Uint 或 reflect.Float64，其他类型返回 reflect.Invalid
+func numberKind(v reflect.Value) reflect.Kind {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+	return reflect.Invalid
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch numberKind(v) {
+	case reflect.Int:
+		return float64(v.Int())
+	case reflect.Uint:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+func isSliceValue(v reflect.Value) bool {
+	return v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array)
+}
+
+// appendSegment 返回追加了 key 的新路径，不修改 segments 的底层数组
+func appendSegment(segments []string, key string) []string {
+	return append(segments[:len(segments):len(segments)], key)
+}
@@ -0,0 +1,117 @@
+package object
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := &HashMap{
+		"name":    "demo",
+		"version": 1,
+		"db":      map[string]any{"host": "a", "port": 5432},
+		"tags":    []any{"x", "y", "z"},
+		"users":   []any{map[string]any{"name": "a", "age": 1}},
+		"removed": true,
+	}
+	b := &HashMap{
+		"name":    "demo",
+		"version": 2,
+		"db":      &HashMap{"host": "b", "port": 5432, "tls": true},
+		"tags":    []string{"x", "w"},
+		"users":   []any{HashMap{"name": "a", "age": 2}},
+		"added":   StringMap{"k": "v"},
+	}
+
+	assert.Equal(t, []Change{
+		{Type: ChangeAdded, Path: "added", New: StringMap{"k": "v"}},
+		{Type: ChangeModified, Path: "db.host", Old: "a", New: "b"},
+		{Type: ChangeAdded, Path: "db.tls", New: true},
+		{Type: ChangeRemoved, Path: "removed", Old: true},
+		{Type: ChangeModified, Path: "tags.1", Old: "y", New: "w"},
+		{Type: ChangeRemoved, Path: "tags.2", Old: "z"},
+		{Type: ChangeModified, Path: "users.0.age", Old: 1, New: 2},
+		{Type: ChangeModified, Path: "version", Old: 1, New: 2},
+	}, Diff(a, b, nil))
+
+	assert.Empty(t, Diff(a, a, nil))
+	assert.Empty(t, Diff(nil, &HashMap{}, nil))
+	assert.Equal(t, []Change{{Type: ChangeAdded, Path: "a", New: 1}}, Diff(nil, &HashMap{"a": 1}, nil))
+}
+
+func TestDiff_Options(t *testing.T) {
+	a := &HashMap{
+		"count": 1,
+		"ratio": float32(0.5),
+		"ids":   []any{1, 2, 3},
+		"items": []any{
+			map[string]any{"sku": "A", "updatedAt": "t1"},
+		},
+		"meta": map[string]any{"etag": "1"},
+	}
+	b := &HashMap{
+		"count": 1.0,
+		"ratio": 0.5,
+		"ids":   []any{3, 1.0, 2},
+		"items": []any{
+			map[string]any{"sku": "A", "updatedAt": "t2"},
+		},
+		"meta": map[string]any{"etag": "2"},
+	}
+
+	changes := Diff(a, b, nil)
+	assert.Len(t, changes, 7)
+
+	changes = Diff(a, b, &DiffOptions{
+		IgnorePaths:      []string{"meta", "items[*].updatedAt"},
+		NumericEqual:     true,
+		IgnoreSliceOrder: true,
+	})
+	assert.Empty(t, changes)
+
+	changes = Diff(&HashMap{"ids": []any{1, 2, 2}}, &HashMap{"ids": []any{2, 4, 1}}, &DiffOptions{IgnoreSliceOrder: true})
+	assert.Equal(t, []Change{
+		{Type: ChangeRemoved, Path: "ids.2", Old: 2},
+		{Type: ChangeAdded, Path: "ids.1", New: 4},
+	}, changes)
+}
+
+func TestNumericEqual(t *testing.T) {
+	cases := []struct {
+		a, b  any
+		equal bool
+		ok    bool
+	}{
+		{1, 1.0, true, true},
+		{int8(-1), uint(1), false, true},
+		{uint64(1 << 63), uint64(1 << 63), true, true},
+		{int64(1<<53 + 1), int64(1 << 53), false, true},
+		{1, "1", false, false},
+	}
+	for _, c := range cases {
+		equal, ok := numericEqual(c.a, c.b)
+		assert.Equal(t, c.equal, equal, "%v %v", c.a, c.b)
+		assert.Equal(t, c.ok, ok, "%v %v", c.a, c.b)
+	}
+}
+
+func TestChange_JSON(t *testing.T) {
+	changes := Diff(&HashMap{"a": nil, "b": 1}, &HashMap{"a": 1, "b": nil, "c": nil}, nil)
+	data, err := json.Marshal(changes)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"type":"modified","path":"a","old":null,"new":1},
+		{"type":"modified","path":"b","old":1,"new":null},
+		{"type":"added","path":"c","new":null}
+	]`, string(data))
+
+	data, err = json.Marshal(Change{Type: ChangeRemoved, Path: "d"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"removed","path":"d","old":null}`, string(data))
+
+	var decoded []Change
+	assert.NoError(t, json.Unmarshal([]byte(`[{"type":"added","path":"c","new":2}]`), &decoded))
+	assert.Equal(t, []Change{{Type: ChangeAdded, Path: "c", New: float64(2)}}, decoded)
+}
@@ -0,0 +1,247 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrKeyConflict 表示两个键展开或还原后落在同一位置
+var ErrKeyConflict = errors.New("key conflict")
+
+// ErrSparseIndex 表示 Unflatten 中方括号下标不是从 0 开始连续的
+var ErrSparseIndex = errors.New("array indexes must be contiguous from 0")
+
+// KeyConflictError 记录发生冲突的两个键
+type KeyConflictError struct {
+	Key   string
+	Other string
+}
+
+func (e *KeyConflictError) Error() string {
+	return fmt.Sprintf("object: key %q conflicts with %q", e.Key, e.Other)
+}
+
+func (e *KeyConflictError) Unwrap() error {
+	return ErrKeyConflict
+}
+
+// ArrayIndexStyle 决定切片下标在展开后的键中的写法
+type ArrayIndexStyle int
+
+const (
+	// ArrayIndexDot 将下标作为普通的一段，如 "items.0.sku"
+	ArrayIndexDot ArrayIndexStyle = iota
+	// ArrayIndexBracket 将下标写在方括号中，如 "items[0].sku"
+	ArrayIndexBracket
+)
+
+// FlattenOptions 是 Flatten 和 Unflatten 的选项，nil 表示使用默认值
+type FlattenOptions struct {
+	// Separator 是各段之间的分隔符，默认为 "."
+	Separator string
+	// ArrayIndex 是切片下标的写法，默认为 ArrayIndexDot
+	ArrayIndex ArrayIndexStyle
+}
+
+func (o *FlattenOptions) separator() string {
+	if o == nil || o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+func (o *FlattenOptions) arrayIndex() ArrayIndexStyle {
+	if o == nil {
+		return ArrayIndexDot
+	}
+	return o.ArrayIndex
+}
+
+// Flatten 将嵌套的 Map 和切片展开为只有一层的 HashMap，例如
+// {"db": {"hosts": ["a"]}} 展开为 {"db.hosts.0": "a"}。
+// 空的 Map 和切片作为值保留，以便 Unflatten 还原。
+// 两个不同的路径展开为同一个键时（如键 "a.b" 与 {"a": {"b": ...}}）返回 *KeyConflictError。
+// 按键的字典序遍历结果（如 Map.All）可以得到确定的输出。
+func Flatten(m *HashMap, opts *FlattenOptions) (*HashMap, error) {
+	result := &HashMap{}
+	if m == nil {
+		return result, nil
+	}
+	f := flattener{sep: opts.separator(), style: opts.arrayIndex(), result: *result, sources: map[string]string{}}
+	for _, k := range slices.Sorted(maps.Keys(*m)) {
+		if err := f.flatten(k, joinPath("", k), (*m)[k]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type flattener struct {
+	sep    string
+	style  ArrayIndexStyle
+	result HashMap
+	// sources 记录每个结果键来自的路径（见 joinPath），用于报告冲突
+	sources map[string]string
+}
+
+func (f *flattener) flatten(key, path string, v any) error {
+	if n, ok := asMapNode(v); ok && n.len() > 0 {
+		for _, k := range n.keys() {
+			child, _ := n.get(k)
+			if err := f.flatten(key+f.sep+k, joinPath(path, k), child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Len() > 0 {
+		for i := 0; i < rv.Len(); i++ {
+			index := strconv.Itoa(i)
+			childKey := key + f.sep + index
+			if f.style == ArrayIndexBracket {
+				childKey = key + "[" + index + "]"
+			}
+			if err := f.flatten(childKey, path+"["+index+"]", rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if other, exists := f.sources[key]; exists {
+		return &KeyConflictError{Key: path, Other: other}
+	}
+	f.sources[key] = path
+	f.result[key] = v
+	return nil
+}
+
+// Unflatten 是 Flatten 的逆操作，按分隔符拆分键，还原嵌套的 *HashMap 和 []any。
+// 使用 ArrayIndexDot 时，子键恰好为 0 到 n-1 的节点还原为切片，其他节点还原为 *HashMap，
+// 因此键恰好为 "0" 到 "n-1" 的 Map 经过 Flatten 和 Unflatten 后会变为切片，不能精确地往返；
+// 使用 ArrayIndexBracket 时只有方括号中的下标还原为切片，可以精确地往返，下标不连续时返回包装 ErrSparseIndex 的错误。
+// 一个键是另一个键的前缀（如 "a" 与 "a.b"），或同一节点下既有下标又有普通的键时，返回 *KeyConflictError。
+func Unflatten(flat *HashMap, opts *FlattenOptions) (*HashMap, error) {
+	root := &flatNode{}
+	if flat != nil {
+		sep, style := opts.separator(), opts.arrayIndex()
+		for _, key := range slices.Sorted(maps.Keys(*flat)) {
+			var segments []pathSegment
+			if style == ArrayIndexBracket {
+				segments = parsePathSep(key, sep)
+			} else {
+				for _, part := range strings.Split(key, sep) {
+					segments = append(segments, pathSegment{key: part})
+				}
+			}
+			if err := root.insert(key, segments, (*flat)[key]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := HashMap{}
+	for k, child := range root.children {
+		var err error
+		if result[k], err = child.build(opts.arrayIndex()); err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// flatNode 是 Unflatten 构建中的节点，叶子节点保存值，其他节点保存子节点
+type flatNode struct {
+	// key 是到达该节点的第一个原始键，用于报告冲突
+	key      string
+	leaf     bool
+	value    any
+	children map[string]*flatNode
+	// bracket 表示子节点以 [n] 的形式给出
+	bracket bool
+}
+
+func (n *flatNode) insert(key string, segments []pathSegment, value any) error {
+	if n.leaf {
+		return &KeyConflictError{Key: key, Other: n.key}
+	}
+	if len(segments) == 0 {
+		if len(n.children) > 0 {
+			return &KeyConflictError{Key: key, Other: n.key}
+		}
+		n.key, n.leaf, n.value = key, true, value
+		return nil
+	}
+
+	seg := segments[0]
+	if n.children == nil {
+		n.children = map[string]*flatNode{}
+		n.bracket = seg.bracket
+	} else if n.bracket != seg.bracket {
+		return &KeyConflictError{Key: key, Other: n.key}
+	}
+	child, exists := n.children[seg.key]
+	if !exists {
+		child = &flatNode{key: key}
+		n.children[seg.key] = child
+	}
+	if n.key == "" {
+		n.key = key
+	}
+	return child.insert(key, segments[1:], value)
+}
+
+func (n *flatNode) build(style ArrayIndexStyle) (any, error) {
+	if n.leaf {
+		return n.value, nil
+	}
+	items, ok, err := n.buildSlice(style)
+	if err != nil || ok {
+		return items, err
+	}
+	m := HashMap{}
+	for k, child := range n.children {
+		if m[k], err = child.build(style); err != nil {
+			return nil, err
+		}
+	}
+	return &m, nil
+}
+
+// buildSlice 在子节点都是下标时还原为 []any，下标必须恰好为 0 到 n-1。
+// ArrayIndexDot 中不连续的下标还原为 *HashMap；ArrayIndexBracket 中返回包装 ErrSparseIndex 的错误，
+// 以免 "a[99999999999]" 这样的键分配巨大的切片
+func (n *flatNode) buildSlice(style ArrayIndexStyle) ([]any, bool, error) {
+	if style == ArrayIndexBracket && !n.bracket {
+		return nil, false, nil
+	}
+	for k := range n.children {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || strconv.Itoa(i) != k {
+			return nil, false, nil
+		}
+		if i >= len(n.children) {
+			if style == ArrayIndexBracket {
+				return nil, false, fmt.Errorf("object: key %q: %w", n.key, ErrSparseIndex)
+			}
+			return nil, false, nil
+		}
+	}
+
+	items := make([]any, len(n.children))
+	for k, child := range n.children {
+		i, _ := strconv.Atoi(k)
+		var err error
+		if items[i], err = child.build(style); err != nil {
+			return nil, false, err
+		}
+	}
+	return items, true, nil
+}
@@ -0,0 +1,158 @@
+package object
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	m := &HashMap{
+		"app": "demo",
+		"db": map[string]any{
+			"hosts": []any{"a", map[string]any{"name": "b"}},
+			"port":  5432,
+		},
+		"labels": StringMap{"env": "prod"},
+		"empty":  map[string]any{},
+		"none":   []any{},
+	}
+
+	flat, err := Flatten(m, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &HashMap{
+		"app":             "demo",
+		"db.hosts.0":      "a",
+		"db.hosts.1.name": "b",
+		"db.port":         5432,
+		"labels.env":      "prod",
+		"empty":           map[string]any{},
+		"none":            []any{},
+	}, flat)
+
+	flat, err = Flatten(m, &FlattenOptions{Separator: "__", ArrayIndex: ArrayIndexBracket})
+	assert.NoError(t, err)
+	assert.Equal(t, "a", (*flat)["db__hosts[0]"])
+	assert.Equal(t, "b", (*flat)["db__hosts[1]__name"])
+
+	var keys []string
+	for k := range flat.Keys() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []string{"app", "db__hosts[0]", "db__hosts[1]__name", "db__port", "empty", "labels__env", "none"}, keys)
+}
+
+func TestFlatten_Conflict(t *testing.T) {
+	_, err := Flatten(&HashMap{"a.b": 1, "a": HashMap{"b": 2}}, nil)
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+	assert.EqualError(t, err, `object: key "[\"a.b\"]" conflicts with "a.b"`)
+}
+
+func TestUnflatten(t *testing.T) {
+	m, err := Unflatten(&HashMap{
+		"app":             "demo",
+		"db.hosts.0":      "a",
+		"db.hosts.1.name": "b",
+		"db.port":         5432,
+		"sparse.0":        "x",
+		"sparse.2":        "y",
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &HashMap{
+		"app": "demo",
+		"db": &HashMap{
+			"hosts": []any{"a", &HashMap{"name": "b"}},
+			"port":  5432,
+		},
+		"sparse": &HashMap{"0": "x", "2": "y"},
+	}, m)
+	assert.Equal(t, "b", NewCollection(m).Get("db.hosts.1.name"))
+
+	m, err = Unflatten(&HashMap{
+		"items[0]__sku": "A",
+		"items[1]":      "B",
+		"codes__0":      "x",
+	}, &FlattenOptions{Separator: "__", ArrayIndex: ArrayIndexBracket})
+	assert.NoError(t, err)
+	assert.Equal(t, &HashMap{
+		"items": []any{&HashMap{"sku": "A"}, "B"},
+		"codes": &HashMap{"0": "x"},
+	}, m)
+}
+
+func TestUnflatten_SparseBracketIndex(t *testing.T) {
+	opts := &FlattenOptions{ArrayIndex: ArrayIndexBracket}
+	for _, flat := range []*HashMap{
+		{"a[99999999999]": 1},
+		{"a[0]": 1, "a[2]": 2},
+		{"a[0][5].b": 1},
+	} {
+		_, err := Unflatten(flat, opts)
+		assert.ErrorIs(t, err, ErrSparseIndex, "%v", flat)
+	}
+
+	// ArrayIndexDot 中不连续的下标还原为 Map
+	m, err := Unflatten(&HashMap{"a.99999999999": 1}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &HashMap{"a": &HashMap{"99999999999": 1}}, m)
+}
+
+func TestUnflatten_Conflict(t *testing.T) {
+	for _, flat := range []*HashMap{
+		{"a": 1, "a.b": 2},
+		{"a.b.c": 1, "a.b": 2},
+	} {
+		_, err := Unflatten(flat, nil)
+		assert.True(t, errors.Is(err, ErrKeyConflict), "%v", flat)
+	}
+
+	_, err := Unflatten(&HashMap{"a[0]": 1, "a.b": 2}, &FlattenOptions{ArrayIndex: ArrayIndexBracket})
+	var conflict *KeyConflictError
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "a[0]", conflict.Key)
+	assert.Equal(t, "a.b", conflict.Other)
+}
+
+func TestFlatten_NumericKeys(t *testing.T) {
+	m := &HashMap{"m": map[string]any{"0": "x", "1": "y"}}
+
+	// ArrayIndexDot 无法区分键为 "0"、"1" 的 Map 和切片
+	flat, err := Flatten(m, nil)
+	assert.NoError(t, err)
+	restored, err := Unflatten(flat, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &HashMap{"m": []any{"x", "y"}}, restored)
+
+	// ArrayIndexBracket 可以精确地往返
+	opts := &FlattenOptions{ArrayIndex: ArrayIndexBracket}
+	flat, err = Flatten(m, opts)
+	assert.NoError(t, err)
+	restored, err = Unflatten(flat, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, &HashMap{"m": &HashMap{"0": "x", "1": "y"}}, restored)
+}
+
+func TestFlatten_RoundTrip(t *testing.T) {
+	var m HashMap
+	assert.NoError(t, JsonDecode([]byte(`{
+		"server": {"port": 8080, "tls": {"enabled": true}},
+		"users": [{"name": "a", "roles": ["admin", "dev"]}, {"name": "b", "roles": []}],
+		"meta": {}
+	}`), &m))
+
+	for _, opts := range []*FlattenOptions{nil, {Separator: "/", ArrayIndex: ArrayIndexBracket}} {
+		flat, err := Flatten(&m, opts)
+		assert.NoError(t, err)
+		restored, err := Unflatten(flat, opts)
+		assert.NoError(t, err)
+
+		want, _ := JsonEncode(m)
+		got, _ := JsonEncode(restored)
+		assert.JSONEq(t, want, got)
+
+		again, err := Flatten(restored, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, flat, again)
+	}
+}
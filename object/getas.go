@@ -0,0 +1,149 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// GetAs 按"点"表示法的路径从 source 读取值并转换为 T。
+// source 可以是 *Collection、*Attribute、*Snapshot 或任意键为 string 的 Map（HashMap、StringMap、map[string]any 等）。
+// 值无法直接断言为 T 时，基础类型按 ToString、ToInt64 等函数的规则转换，
+// 其他类型（如结构体、[]int、map[string]int）通过 JSON 编解码转换。
+// 读取或转换失败时返回 *PathError。
+func GetAs[T any](source any, path string) (T, error) {
+	var zero T
+	m, err := sourceMap(source)
+	if err != nil {
+		return zero, err
+	}
+	return getAs(m, path, typeName[T](), convertTo[T])
+}
+
+// MustGetAs 与 GetAs 相同，但失败时 panic
+func MustGetAs[T any](source any, path string) T {
+	v, err := GetAs[T](source, path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// sourceMap 返回 GetAs 支持的数据源底层的 HashMap
+func sourceMap(source any) (*HashMap, error) {
+	switch s := source.(type) {
+	case *Collection:
+		if s != nil {
+			return s.items, nil
+		}
+	case *HashMap:
+		if s != nil {
+			return s, nil
+		}
+	case HashMap:
+		return &s, nil
+	case *Attribute:
+		if s != nil {
+			return &s.Attributes, nil
+		}
+	case *Snapshot:
+		if s != nil {
+			return s.data, nil
+		}
+	case map[string]any:
+		m := HashMap(s)
+		return &m, nil
+	default:
+		if n, ok := asMapNode(source); ok && !n.isNil() {
+			m := n.toHashMap()
+			return &m, nil
+		}
+		return nil, fmt.Errorf("object: unsupported source type %T", source)
+	}
+	return nil, fmt.Errorf("object: nil source %T", source)
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// convertTo 将 v 转换为 T
+func convertTo[T any](v any) (T, error) {
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+
+	var zero T
+	var result any
+	var err error
+	switch any(zero).(type) {
+	case string:
+		result, err = ToString(v)
+	case bool:
+		result, err = ToBool(v)
+	case time.Duration:
+		result, err = ToDuration(v)
+	case time.Time:
+		result, err = ToTime(v)
+	case []string:
+		result, err = ToStringSlice(v)
+	case *HashMap:
+		result, err = ToHashMap(v)
+	default:
+		return convertReflect[T](v)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return result.(T), nil
+}
+
+// convertReflect 处理其他数值类型，其余类型通过 JSON 编解码转换
+func convertReflect[T any](v any) (T, error) {
+	var zero T
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	target := reflect.New(rt).Elem()
+
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := ToInt64(v)
+		if err != nil {
+			return zero, err
+		}
+		if target.OverflowInt(n) {
+			return zero, fmt.Errorf("%d overflows %s", n, rt)
+		}
+		target.SetInt(n)
+		return target.Interface().(T), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := ToInt64(v)
+		if err != nil {
+			return zero, err
+		}
+		if n < 0 || target.OverflowUint(uint64(n)) {
+			return zero, fmt.Errorf("%d overflows %s", n, rt)
+		}
+		target.SetUint(uint64(n))
+		return target.Interface().(T), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := ToFloat64(v)
+		if err != nil {
+			return zero, err
+		}
+		if target.OverflowFloat(f) {
+			return zero, fmt.Errorf("%v overflows %s", f, rt)
+		}
+		target.SetFloat(f)
+		return target.Interface().(T), nil
+	}
+
+	data, err := JsonEncode(v)
+	if err != nil {
+		return zero, err
+	}
+	var result T
+	if err := JsonDecode([]byte(data), &result); err != nil {
+		return zero, err
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAs(t *testing.T) {
+	var decoded HashMap
+	assert.NoError(t, JsonDecode([]byte(`{
+		"port": 8080,
+		"ratio": 0.25,
+		"timeout": "5s",
+		"ids": [1, 2, 3],
+		"owner": {"name": "alice", "age": 30},
+		"limits": {"cpu": 2, "mem": 512}
+	}`), &decoded))
+	c := NewCollection(&HashMap{"server": decoded})
+
+	port, err := GetAs[int](c, "server.port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	assert.Equal(t, uint16(8080), MustGetAs[uint16](c, "server.port"))
+	assert.Equal(t, float32(0.25), MustGetAs[float32](c, "server.ratio"))
+	assert.Equal(t, "8080", MustGetAs[string](c, "server.port"))
+	assert.Equal(t, 5*time.Second, MustGetAs[time.Duration](c, "server.timeout"))
+	assert.Equal(t, []int{1, 2, 3}, MustGetAs[[]int](c, "server.ids"))
+	assert.Equal(t, 2, MustGetAs[int](c, "server.ids[-2]"))
+	assert.Equal(t, map[string]int{"cpu": 2, "mem": 512}, MustGetAs[map[string]int](c, "server.limits"))
+
+	type owner struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	assert.Equal(t, owner{Name: "alice", Age: 30}, MustGetAs[owner](c, "server.owner"))
+
+	// 直接断言成功时返回原值
+	m := MustGetAs[map[string]any](c, "server.owner")
+	m["name"] = "bob"
+	assert.Equal(t, "bob", c.GetString("server.owner.name"))
+
+	_, err = GetAs[int8](c, "server.port")
+	assert.ErrorContains(t, err, "8080 overflows int8")
+	_, err = GetAs[int](c, "server.ratio")
+	var pathErr *PathError
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "server.ratio", pathErr.Path)
+	assert.Equal(t, "int", pathErr.Want)
+	_, err = GetAs[int](c, "server.missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Panics(t, func() { MustGetAs[bool](c, "server.owner") })
+}
+
+func TestGetAs_Sources(t *testing.T) {
+	raw := map[string]any{"a": map[string]any{"b": "12"}}
+	hm := HashMap(raw)
+
+	assert.Equal(t, 12, MustGetAs[int](raw, "a.b"))
+	assert.Equal(t, 12, MustGetAs[int](hm, "a.b"))
+	assert.Equal(t, 12, MustGetAs[int](&hm, "a.b"))
+	assert.Equal(t, 12, MustGetAs[int](NewAttribute(&hm), "a.b"))
+
+	_, err := GetAs[int](42, "a")
+	assert.EqualError(t, err, "object: unsupported source type int")
+	_, err = GetAs[int]((*Collection)(nil), "a")
+	assert.Error(t, err)
+}
@@ -0,0 +1,256 @@
+package object
+
+import (
+	"time"
+)
+
+// lookupPath 按"点"表示法的路径读取 Map 中的值，值为 nil 时视为不存在
+func lookupPath[V any](m *Map[V], path string) (any, bool) {
+	if m == nil || path == "" {
+		return nil, false
+	}
+	segments := parsePath(path)
+	var v any
+	first, ok := (*m)[segments[0].key]
+	if ok {
+		v, ok = getPath(any(first), segments[1:])
+	}
+	if !ok {
+		// 按路径找不到时，把 path 当作顶层的键原样查找
+		var literal V
+		if literal, ok = (*m)[path]; ok {
+			v = literal
+		}
+	}
+	if !ok || v == nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// getAs 读取路径上的值并按 convert 转换，失败时返回 *PathError
+func getAs[V, T any](m *Map[V], path, want string, convert func(any) (T, error)) (T, error) {
+	v, ok := lookupPath(m, path)
+	if !ok {
+		var zero T
+		return zero, &PathError{Path: path, Want: want, Err: ErrNotFound}
+	}
+	result, err := convert(v)
+	if err != nil {
+		var zero T
+		return zero, &PathError{Path: path, Type: typeOf(v), Want: want, Err: err}
+	}
+	return result, nil
+}
+
+// 以下类型化的读取方法接受"点"表示法的路径（与 Collection.Get 相同），并按 ToString 等函数的规则转换。
+// 不带 E 后缀的方法在路径不存在或无法转换时返回默认值；
+// 带 E 后缀的方法返回 *PathError，其中包含路径和实际类型。
+// 与 Collection.Get 不同，存在的零值（如 0、false、""）会原样返回。
+
+// GetStringE 读取路径上的值并转换为字符串
+func (m *Map[V]) GetStringE(path string) (string, error) {
+	return getAs(m, path, "string", ToString)
+}
+
+// GetString 读取路径上的值并转换为字符串，失败时返回 defaultValue
+func (m *Map[V]) GetString(path string, defaultValue ...string) string {
+	if v, err := m.GetStringE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetIntE 读取路径上的值并转换为 int
+func (m *Map[V]) GetIntE(path string) (int, error) {
+	return getAs(m, path, "int", ToInt)
+}
+
+// GetInt 读取路径上的值并转换为 int，失败时返回 defaultValue
+func (m *Map[V]) GetInt(path string, defaultValue ...int) int {
+	if v, err := m.GetIntE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetInt64E 读取路径上的值并转换为 int64
+func (m *Map[V]) GetInt64E(path string) (int64, error) {
+	return getAs(m, path, "int64", ToInt64)
+}
+
+// GetInt64 读取路径上的值并转换为 int64，失败时返回 defaultValue
+func (m *Map[V]) GetInt64(path string, defaultValue ...int64) int64 {
+	if v, err := m.GetInt64E(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetFloat64E 读取路径上的值并转换为 float64
+func (m *Map[V]) GetFloat64E(path string) (float64, error) {
+	return getAs(m, path, "float64", ToFloat64)
+}
+
+// GetFloat64 读取路径上的值并转换为 float64，失败时返回 defaultValue
+func (m *Map[V]) GetFloat64(path string, defaultValue ...float64) float64 {
+	if v, err := m.GetFloat64E(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetBoolE 读取路径上的值并转换为布尔值
+func (m *Map[V]) GetBoolE(path string) (bool, error) {
+	return getAs(m, path, "bool", ToBool)
+}
+
+// GetBool 读取路径上的值并转换为布尔值，失败时返回 defaultValue
+func (m *Map[V]) GetBool(path string, defaultValue ...bool) bool {
+	if v, err := m.GetBoolE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetDurationE 读取路径上的值并转换为 time.Duration
+func (m *Map[V]) GetDurationE(path string) (time.Duration, error) {
+	return getAs(m, path, "time.Duration", ToDuration)
+}
+
+// GetDuration 读取路径上的值并转换为 time.Duration，失败时返回 defaultValue
+func (m *Map[V]) GetDuration(path string, defaultValue ...time.Duration) time.Duration {
+	if v, err := m.GetDurationE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetTimeE 读取路径上的值并转换为 time.Time
+func (m *Map[V]) GetTimeE(path string) (time.Time, error) {
+	return getAs(m, path, "time.Time", ToTime)
+}
+
+// GetTime 读取路径上的值并转换为 time.Time，失败时返回 defaultValue
+func (m *Map[V]) GetTime(path string, defaultValue ...time.Time) time.Time {
+	if v, err := m.GetTimeE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetStringSliceE 读取路径上的值并转换为 []string
+func (m *Map[V]) GetStringSliceE(path string) ([]string, error) {
+	return getAs(m, path, "[]string", ToStringSlice)
+}
+
+// GetStringSlice 读取路径上的值并转换为 []string，失败时返回 defaultValue
+func (m *Map[V]) GetStringSlice(path string, defaultValue ...[]string) []string {
+	if v, err := m.GetStringSliceE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetHashMapE 读取路径上的值并转换为 *HashMap
+func (m *Map[V]) GetHashMapE(path string) (*HashMap, error) {
+	return getAs(m, path, "*HashMap", ToHashMap)
+}
+
+// GetHashMap 读取路径上的值并转换为 *HashMap，失败时返回 defaultValue
+func (m *Map[V]) GetHashMap(path string, defaultValue ...*HashMap) *HashMap {
+	if v, err := m.GetHashMapE(path); err == nil {
+		return v
+	}
+	return GetDefaultValue(defaultValue...)
+}
+
+// GetStringE 读取路径上的值并转换为字符串，见 Map.GetStringE
+func (c *Collection) GetStringE(key string) (string, error) {
+	return c.items.GetStringE(key)
+}
+
+// GetString 读取路径上的值并转换为字符串，失败时返回 defaultValue
+func (c *Collection) GetString(key string, defaultValue ...string) string {
+	return c.items.GetString(key, defaultValue...)
+}
+
+// GetIntE 读取路径上的值并转换为 int，见 Map.GetIntE
+func (c *Collection) GetIntE(key string) (int, error) {
+	return c.items.GetIntE(key)
+}
+
+// GetInt 读取路径上的值并转换为 int，失败时返回 defaultValue
+func (c *Collection) GetInt(key string, defaultValue ...int) int {
+	return c.items.GetInt(key, defaultValue...)
+}
+
+// GetInt64E 读取路径上的值并转换为 int64，见 Map.GetInt64E
+func (c *Collection) GetInt64E(key string) (int64, error) {
+	return c.items.GetInt64E(key)
+}
+
+// GetInt64 读取路径上的值并转换为 int64，失败时返回 defaultValue
+func (c *Collection) GetInt64(key string, defaultValue ...int64) int64 {
+	return c.items.GetInt64(key, defaultValue...)
+}
+
+// GetFloat64E 读取路径上的值并转换为 float64，见 Map.GetFloat64E
+func (c *Collection) GetFloat64E(key string) (float64, error) {
+	return c.items.GetFloat64E(key)
+}
+
+// GetFloat64 读取路径上的值并转换为 float64，失败时返回 defaultValue
+func (c *Collection) GetFloat64(key string, defaultValue ...float64) float64 {
+	return c.items.GetFloat64(key, defaultValue...)
+}
+
+// GetBoolE 读取路径上的值并转换为布尔值，见 Map.GetBoolE
+func (c *Collection) GetBoolE(key string) (bool, error) {
+	return c.items.GetBoolE(key)
+}
+
+// GetBool 读取路径上的值并转换为布尔值，失败时返回 defaultValue
+func (c *Collection) GetBool(key string, defaultValue ...bool) bool {
+	return c.items.GetBool(key, defaultValue...)
+}
+
+// GetDurationE 读取路径上的值并转换为 time.Duration，见 Map.GetDurationE
+func (c *Collection) GetDurationE(key string) (time.Duration, error) {
+	return c.items.GetDurationE(key)
+}
+
+// GetDuration 读取路径上的值并转换为 time.Duration，失败时返回 defaultValue
+func (c *Collection) GetDuration(key string, defaultValue ...time.Duration) time.Duration {
+	return c.items.GetDuration(key, defaultValue...)
+}
+
+// GetTimeE 读取路径上的值并转换为 time.Time，见 Map.GetTimeE
+func (c *Collection) GetTimeE(key string) (time.Time, error) {
+	return c.items.GetTimeE(key)
+}
+
+// GetTime 读取路径上的值并转换为 time.Time，失败时返回 defaultValue
+func (c *Collection) GetTime(key string, defaultValue ...time.Time) time.Time {
+	return c.items.GetTime(key, defaultValue...)
+}
+
+// GetStringSliceE 读取路径上的值并转换为 []string，见 Map.GetStringSliceE
+func (c *Collection) GetStringSliceE(key string) ([]string, error) {
+	return c.items.GetStringSliceE(key)
+}
+
+// GetStringSlice 读取路径上的值并转换为 []string，失败时返回 defaultValue
+func (c *Collection) GetStringSlice(key string, defaultValue ...[]string) []string {
+	return c.items.GetStringSlice(key, defaultValue...)
+}
+
+// GetHashMapE 读取路径上的值并转换为 *HashMap，见 Map.GetHashMapE
+func (c *Collection) GetHashMapE(key string) (*HashMap, error) {
+	return c.items.GetHashMapE(key)
+}
+
+// GetHashMap 读取路径上的值并转换为 *HashMap，失败时返回 defaultValue
+func (c *Collection) GetHashMap(key string, defaultValue ...*HashMap) *HashMap {
+	return c.items.GetHashMap(key, defaultValue...)
+}
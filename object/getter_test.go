@@ -0,0 +1,123 @@
+package object
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTypedCollection(t *testing.T) *Collection {
+	t.Helper()
+	var decoded HashMap
+	dec := json.NewDecoder(strings.NewReader(`{"port": 8080, "ratio": 0.5, "big": 12345678901234}`))
+	dec.UseNumber()
+	assert.NoError(t, dec.Decode(&decoded))
+
+	return NewCollection(&HashMap{
+		"name":    "app",
+		"json":    &decoded,
+		"debug":   "1",
+		"enabled": false,
+		"retries": "3",
+		"timeout": "1m30s",
+		"ttl":     90,
+		"started": "2024-05-01T10:00:00Z",
+		"epoch":   int64(1714557600),
+		"tags":    []any{"a", 2, true},
+		"db": map[string]any{
+			"hosts": []string{"h1", "h2"},
+			"pool":  map[string]int{"max": 10},
+		},
+		"items": []any{&HashMap{"qty": "7"}},
+	})
+}
+
+func TestCollection_TypedGetters(t *testing.T) {
+	c := newTypedCollection(t)
+
+	assert.Equal(t, "app", c.GetString("name"))
+	assert.Equal(t, "8080", c.GetString("json.port"))
+	assert.Equal(t, 8080, c.GetInt("json.port"))
+	assert.Equal(t, int64(12345678901234), c.GetInt64("json.big"))
+	assert.Equal(t, 0.5, c.GetFloat64("json.ratio"))
+	assert.Equal(t, 3, c.GetInt("retries"))
+	assert.Equal(t, 7, c.GetInt("items[0].qty"))
+	assert.True(t, c.GetBool("debug"))
+	assert.False(t, c.GetBool("enabled", true))
+	assert.Equal(t, 90*time.Second, c.GetDuration("timeout"))
+	assert.Equal(t, 90*time.Second, c.GetDuration("ttl"))
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), c.GetTime("started").UTC())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), c.GetTime("epoch").UTC())
+	assert.Equal(t, []string{"a", "2", "true"}, c.GetStringSlice("tags"))
+	assert.Equal(t, []string{"h1", "h2"}, c.GetStringSlice("db.hosts"))
+	assert.Equal(t, &HashMap{"max": 10}, c.GetHashMap("db.pool"))
+
+	// 共享数据：通过返回的 *HashMap 修改会反映到集合中
+	(*c.GetHashMap("db"))["user"] = "root"
+	assert.Equal(t, "root", c.GetString("db.user"))
+
+	// 默认值
+	assert.Equal(t, "none", c.GetString("missing", "none"))
+	assert.Equal(t, 5, c.GetInt("name", 5))
+	assert.Equal(t, 0, c.GetInt("name"))
+	assert.Equal(t, time.Second, c.GetDuration("name", time.Second))
+}
+
+func TestCollection_TypedGettersE(t *testing.T) {
+	c := newTypedCollection(t)
+
+	_, err := c.GetIntE("db.missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, `object: "db.missing" not found`)
+
+	_, err = c.GetIntE("json.ratio")
+	var pathErr *PathError
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "json.ratio", pathErr.Path)
+	assert.Equal(t, "json.Number", pathErr.Type)
+	assert.Equal(t, "int", pathErr.Want)
+
+	_, err = c.GetStringE("db")
+	assert.EqualError(t, err, `object: "db" is map[string]interface {}, cannot convert to string: unsupported type`)
+
+	_, err = c.GetBoolE("name")
+	assert.ErrorContains(t, err, `invalid boolean "app"`)
+
+	_, err = c.GetStringSliceE("db.pool")
+	assert.Error(t, err)
+}
+
+func TestHashMap_TypedGetters(t *testing.T) {
+	m := &HashMap{"a": map[string]any{"b": []any{"1", "x"}}}
+	assert.Equal(t, 1, m.GetInt("a.b.0"))
+	assert.Equal(t, -1, m.GetInt("a.b.1", -1))
+
+	sm := &StringMap{"on": "yes", "n": "42"}
+	assert.True(t, sm.GetBool("on"))
+	assert.Equal(t, 42.0, sm.GetFloat64("n"))
+	_, err := sm.GetIntE("on.x")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAttribute_GetString(t *testing.T) {
+	attr := NewAttribute(&HashMap{"count": 3, "name": ""})
+	assert.Equal(t, "3", attr.GetString("count", "x"))
+	assert.Equal(t, "x", attr.GetString("name", "x"))
+	assert.Equal(t, "x", attr.GetString("missing", "x"))
+}
+
+func TestToDuration_Range(t *testing.T) {
+	d, err := ToDuration(1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	for _, v := range []any{1e300, -1e300, "1e300", math.NaN(), math.Inf(1)} {
+		_, err := ToDuration(v)
+		assert.ErrorIs(t, err, strconv.ErrRange, "%v", v)
+	}
+}
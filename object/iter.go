@@ -0,0 +1,127 @@
+package object
+
+import (
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// All 按键的字典序遍历 Map 的键值对
+func (m *Map[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		if m == nil {
+			return
+		}
+		for _, k := range slices.Sorted(maps.Keys(*m)) {
+			if !yield(k, (*m)[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Keys 按字典序遍历 Map 的键
+func (m *Map[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values 按键的字典序遍历 Map 的值
+func (m *Map[V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Walk 递归遍历 Map 中的每个叶子节点，产生"点"表示法的路径和值，例如 ("items.0.sku", "A1")。
+// 包含 "."、"[" 或 "]" 的键写作 ["a.b"]，因此不同的结构不会产生相同的路径，产生的路径都可以交给 Get 读取。
+// 会进入各种 Map（HashMap、map[string]any、StringMap 等）和切片；空的 Map 和切片作为叶子节点产生。
+// Map 按键的字典序、切片按下标顺序遍历，因此顺序是确定的。
+func (m *Map[V]) Walk() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for k, v := range m.All() {
+			if !walkValue(joinPath("", k), v, yield) {
+				return
+			}
+		}
+	}
+}
+
+// walkValue 遍历 v 下的叶子节点，返回 false 表示调用方已停止遍历
+func walkValue(path string, v any, yield func(string, any) bool) bool {
+	if n, ok := asMapNode(v); ok {
+		if n.len() == 0 {
+			return yield(path, v)
+		}
+		for _, k := range n.keys() {
+			child, _ := n.get(k)
+			if !walkValue(joinPath(path, k), child, yield) {
+				return false
+			}
+		}
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Len() > 0 {
+		for i := 0; i < rv.Len(); i++ {
+			if !walkValue(path+"."+strconv.Itoa(i), rv.Index(i).Interface(), yield) {
+				return false
+			}
+		}
+		return true
+	}
+	return yield(path, v)
+}
+
+// Items 按键的字典序遍历集合顶层的键值对。
+// （All 返回底层的 *HashMap，c.All().All() 与 Items 相同。）
+func (c *Collection) Items() iter.Seq2[string, any] {
+	return c.items.All()
+}
+
+// Keys 按字典序遍历集合顶层的键
+func (c *Collection) Keys() iter.Seq[string] {
+	return c.items.Keys()
+}
+
+// Values 按键的字典序遍历集合顶层的值
+func (c *Collection) Values() iter.Seq[any] {
+	return c.items.Values()
+}
+
+// Walk 递归遍历集合中的每个叶子节点，见 Map.Walk
+func (c *Collection) Walk() iter.Seq2[string, any] {
+	return c.items.Walk()
+}
+
+// All 按键的字典序遍历属性的键值对
+func (attr *Attribute) All() iter.Seq2[string, any] {
+	return attr.Attributes.All()
+}
+
+// Keys 按字典序遍历属性的键
+func (attr *Attribute) Keys() iter.Seq[string] {
+	return attr.Attributes.Keys()
+}
+
+// Values 按键的字典序遍历属性的值
+func (attr *Attribute) Values() iter.Seq[any] {
+	return attr.Attributes.Values()
+}
+
+// Walk 递归遍历属性中的每个叶子节点，见 Map.Walk
+func (attr *Attribute) Walk() iter.Seq2[string, any] {
+	return attr.Attributes.Walk()
+}
@@ -0,0 +1,143 @@
+package object
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap_Iterators(t *testing.T) {
+	m := &StringMap{"b": "2", "a": "1", "c": "3"}
+
+	var pairs []string
+	for k, v := range m.All() {
+		pairs = append(pairs, k+"="+v)
+	}
+	assert.Equal(t, []string{"a=1", "b=2", "c=3"}, pairs)
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()))
+	assert.Equal(t, []string{"1", "2", "3"}, slices.Collect(m.Values()))
+
+	// 提前停止
+	for k := range m.Keys() {
+		assert.Equal(t, "a", k)
+		break
+	}
+
+	var nilMap *HashMap
+	assert.Empty(t, slices.Collect(nilMap.Keys()))
+}
+
+func TestCollection_Walk(t *testing.T) {
+	c := NewCollection(&HashMap{
+		"z": 1,
+		"user": &HashMap{
+			"name":  "alice",
+			"roles": []string{"admin", "dev"},
+		},
+		"items": []any{
+			map[string]any{"sku": "A1", "qty": 2},
+			HashMap{},
+		},
+		"empty": []any{},
+		"nil":   nil,
+	})
+
+	type leaf struct {
+		path  string
+		value any
+	}
+	var leaves []leaf
+	for path, v := range c.Walk() {
+		leaves = append(leaves, leaf{path, v})
+	}
+	assert.Equal(t, []leaf{
+		{"empty", []any{}},
+		{"items.0.qty", 2},
+		{"items.0.sku", "A1"},
+		{"items.1", HashMap{}},
+		{"nil", nil},
+		{"user.name", "alice"},
+		{"user.roles.0", "admin"},
+		{"user.roles.1", "dev"},
+		{"z", 1},
+	}, leaves)
+
+	// 每个路径都可以用 Get 读回
+	for path, v := range c.Walk() {
+		if v != nil && !IsZero(v) {
+			assert.Equal(t, v, c.Get(path), path)
+		}
+	}
+
+	var n int
+	for range c.Walk() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, n)
+
+	assert.Equal(t, []string{"empty", "items", "nil", "user", "z"}, slices.Collect(c.Keys()))
+	assert.Len(t, slices.Collect(c.Values()), 5)
+	var keys []string
+	for k := range c.Items() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, slices.Collect(c.Keys()), keys)
+}
+
+func TestAttribute_Iterators(t *testing.T) {
+	attr := NewAttribute(&HashMap{"b": map[string]any{"c": 1}, "a": "x"})
+	assert.Equal(t, []string{"a", "b"}, slices.Collect(attr.Keys()))
+	assert.Equal(t, "x", slices.Collect(attr.Values())[0])
+
+	var paths []string
+	for p := range attr.Walk() {
+		paths = append(paths, p)
+	}
+	assert.Equal(t, []string{"a", "b.c"}, paths)
+
+	for k, v := range attr.All() {
+		assert.Equal(t, "a", k)
+		assert.Equal(t, "x", v)
+		break
+	}
+}
+
+func TestWalk_EscapedKeys(t *testing.T) {
+	walk := func(m *HashMap) []string {
+		var paths []string
+		for path := range m.Walk() {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	assert.Equal(t, []string{`["a.b"]`}, walk(&HashMap{"a.b": 1}))
+	assert.Equal(t, []string{"a.b"}, walk(&HashMap{"a": HashMap{"b": 1}}))
+	assert.Equal(t, []string{`x["k[0]"].0`, `x["y.z"]`}, walk(&HashMap{
+		"x": map[string]any{"y.z": 1, "k[0]": []any{2}},
+	}))
+}
+
+func TestWalk_PathsResolve(t *testing.T) {
+	m := &HashMap{
+		"a.b": 1,
+		"x": map[string]any{
+			"y.z":   "v",
+			"k[0]":  []any{2, map[string]any{"q\"uote": true}},
+			"plain": HashMap{"n": 3.5},
+		},
+		"list": []any{"s", []any{4}},
+	}
+	c := NewCollection(m)
+	n := 0
+	for path, v := range m.Walk() {
+		n++
+		assert.True(t, c.Has(path), path)
+		assert.Equal(t, v, c.Get(path), path)
+	}
+	assert.Equal(t, 7, n)
+}
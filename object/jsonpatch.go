@@ -0,0 +1,453 @@
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch（RFC 6902）的操作
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// ErrTestFailed 表示 test 操作的值与文档中的值不相等
+var ErrTestFailed = errors.New("test failed")
+
+// PatchOperation 是 JSON Patch 中的一个操作，Path 和 From 为 RFC 6901 的 JSON Pointer
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value any
+}
+
+// Patch 是 JSON Patch 文档
+type Patch []PatchOperation
+
+// patchOperationJSON 是 PatchOperation 的 JSON 形式，Value 为 RawMessage 以便区分缺失和 null
+type patchOperationJSON struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path,omitempty"`
+	From  *string         `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalJSON 只输出操作需要的字段，add、replace 和 test 的 nil 值输出为 null
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	aux := patchOperationJSON{Op: op.Op, Path: &op.Path}
+	switch op.Op {
+	case PatchMove, PatchCopy:
+		aux.From = &op.From
+	case PatchAdd, PatchReplace, PatchTest:
+		value, err := JsonEncode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		aux.Value = json.RawMessage(value)
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON 解析操作并检查操作名称和必需的字段
+func (op *PatchOperation) UnmarshalJSON(data []byte) error {
+	var aux patchOperationJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*op = PatchOperation{Op: aux.Op}
+	if aux.Path == nil {
+		return fmt.Errorf("object: patch operation %q is missing \"path\"", aux.Op)
+	}
+	op.Path = *aux.Path
+
+	switch aux.Op {
+	case PatchRemove:
+	case PatchMove, PatchCopy:
+		if aux.From == nil {
+			return fmt.Errorf("object: patch operation %q is missing \"from\"", aux.Op)
+		}
+		op.From = *aux.From
+	case PatchAdd, PatchReplace, PatchTest:
+		if len(aux.Value) == 0 {
+			return fmt.Errorf("object: patch operation %q is missing \"value\"", aux.Op)
+		}
+		if err := JsonDecode(aux.Value, &op.Value); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("object: unknown patch operation %q", aux.Op)
+	}
+	return nil
+}
+
+// DecodePatch 解析 JSON Patch 文档
+func DecodePatch(data []byte) (Patch, error) {
+	var patch Patch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+// PatchError 记录 JSON Patch 中失败的操作
+type PatchError struct {
+	// Index 是失败的操作在 Patch 中的下标
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("object: patch operation %d (%s %q): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatch 将 patch 应用到 m。操作依次在 m 的副本上执行，
+// 全部成功后才写回 m；任何一个操作失败时 m 保持不变，并返回 *PatchError。
+// 路径不存在时错误包装 ErrNotFound，test 失败时包装 ErrTestFailed
+func ApplyPatch(m *HashMap, patch Patch) error {
+	if m == nil {
+		return errors.New("object: cannot apply patch to nil map")
+	}
+	doc := cloneValue(m).(*HashMap)
+	for i, op := range patch {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			return &PatchError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+
+	clear(*m)
+	maps.Copy(*m, *doc)
+	return nil
+}
+
+// ApplyPatch 将 patch 应用到集合，见 ApplyPatch
+func (c *Collection) ApplyPatch(patch Patch) error {
+	return ApplyPatch(c.items, patch)
+}
+
+// ApplyPatch 在写锁内将 patch 应用到集合，见 ApplyPatch
+func (s *SafeCollection) ApplyPatch(patch Patch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.ApplyPatch(patch)
+}
+
+func applyOperation(doc *HashMap, op PatchOperation) (*HashMap, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case PatchAdd:
+		return pointerAdd(doc, path, cloneValue(op.Value))
+	case PatchRemove:
+		return pointerRemove(doc, path)
+	case PatchReplace:
+		if _, err := pointerGet(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return pointerAdd(doc, path, cloneValue(op.Value))
+		}
+		updated, err := updateAt(doc, path, func(parent any, token string) (any, error) {
+			return replaceChild(parent, token, cloneValue(op.Value)), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return updated.(*HashMap), nil
+	case PatchMove, PatchCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from %q: %w", op.From, err)
+		}
+		if op.Op == PatchCopy {
+			return pointerAdd(doc, path, cloneValue(value))
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") || len(from) == 0 {
+			return nil, fmt.Errorf("cannot move %q into its own child", op.From)
+		}
+		if doc, err = pointerRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	case PatchTest:
+		value, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !newDiffer(&DiffOptions{NumericEqual: true}).equal(nil, value, op.Value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// pointerGet 读取 JSON Pointer 指向的值
+func pointerGet(doc *HashMap, path []string) (any, error) {
+	var current any = doc
+	for _, token := range path {
+		child, err := childOf(current, token)
+		if err != nil {
+			return nil, err
+		}
+		current = child
+	}
+	return current, nil
+}
+
+// pointerAdd 在 Map 中添加或替换键，在切片中插入元素（"-" 表示末尾）
+func pointerAdd(doc *HashMap, path []string, value any) (*HashMap, error) {
+	if len(path) == 0 {
+		m, err := ToHashMap(value)
+		if err != nil {
+			return nil, fmt.Errorf("document root must be an object, got %s", typeOf(value))
+		}
+		return m, nil
+	}
+	updated, err := updateAt(doc, path, func(parent any, token string) (any, error) {
+		if n, ok := asMapNode(parent); ok {
+			return setMapKey(parent, n, token, value), nil
+		}
+		rv := reflect.ValueOf(parent)
+		i := rv.Len()
+		if token != "-" {
+			var err error
+			if i, err = arrayIndex(token, rv.Len()+1); err != nil {
+				return nil, err
+			}
+		}
+		items := toAnySlice(rv)
+		items = append(items[:i], append([]any{value}, items[i:]...)...)
+		return sliceOfType(rv.Type(), items), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated.(*HashMap), nil
+}
+
+// pointerRemove 删除 Map 中的键或切片中的元素
+func pointerRemove(doc *HashMap, path []string) (*HashMap, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the root")
+	}
+	updated, err := updateAt(doc, path, func(parent any, token string) (any, error) {
+		if _, err := childOf(parent, token); err != nil {
+			return nil, err
+		}
+		if n, ok := asMapNode(parent); ok {
+			n.delete(token)
+			return parent, nil
+		}
+		rv := reflect.ValueOf(parent)
+		i, _ := arrayIndex(token, rv.Len())
+		items := toAnySlice(rv)
+		return sliceOfType(rv.Type(), append(items[:i], items[i+1:]...)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated.(*HashMap), nil
+}
+
+// updateAt 找到 path 的父容器，调用 fn 修改后将结果逐级写回，返回修改后的 current
+func updateAt(current any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		if !isContainer(current) {
+			return nil, fmt.Errorf("parent is %s, not an object or array", typeOf(current))
+		}
+		return fn(current, path[0])
+	}
+	child, err := childOf(current, path[0])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateAt(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return replaceChild(current, path[0], updated), nil
+}
+
+// childOf 返回容器中 token 指向的值，token 必须已存在
+func childOf(current any, token string) (any, error) {
+	if n, ok := asMapNode(current); ok {
+		if !n.isNil() {
+			if v, exists := n.get(token); exists {
+				return v, nil
+			}
+		}
+		return nil, ErrNotFound
+	}
+	rv := reflect.ValueOf(current)
+	if !isSliceValue(rv) {
+		return nil, ErrNotFound
+	}
+	i, err := arrayIndex(token, rv.Len())
+	if err != nil {
+		return nil, err
+	}
+	return rv.Index(i).Interface(), nil
+}
+
+// replaceChild 替换容器中 token 指向的值，token 已由 childOf 检查
+func replaceChild(current any, token string, value any) any {
+	if n, ok := asMapNode(current); ok {
+		return setMapKey(current, n, token, value)
+	}
+	rv := reflect.ValueOf(current)
+	i, _ := arrayIndex(token, rv.Len())
+	items := toAnySlice(rv)
+	items[i] = value
+	return sliceOfType(rv.Type(), items)
+}
+
+func isContainer(v any) bool {
+	_, ok := asMapNode(v)
+	return ok || isSliceValue(reflect.ValueOf(v))
+}
+
+// arrayIndex 按 RFC 6901 解析数组下标：十进制、无前导零，且小于 n
+func arrayIndex(token string, n int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= n {
+		return 0, fmt.Errorf("array index %q out of range: %w", token, ErrNotFound)
+	}
+	return i, nil
+}
+
+func toAnySlice(rv reflect.Value) []any {
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
+
+// sliceOfType 在元素都可以赋值时返回类型为 t 的切片，否则返回 []any
+func sliceOfType(t reflect.Type, items []any) any {
+	if t.Kind() != reflect.Slice {
+		return items
+	}
+	result := reflect.MakeSlice(t, len(items), len(items))
+	for i, item := range items {
+		v := reflect.ValueOf(item)
+		if !v.IsValid() {
+			continue
+		}
+		if !v.Type().AssignableTo(t.Elem()) {
+			return items
+		}
+		result.Index(i).Set(v)
+	}
+	return result.Interface()
+}
+
+// parsePointer 解析 RFC 6901 的 JSON Pointer，"" 表示整个文档
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid escape in JSON pointer %q", pointer)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// formatPointer 将路径的各段格式化为 JSON Pointer
+func formatPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// CreatePatch 生成将 a 变为 b 的 JSON Patch。
+// 变更与 Diff（不带选项）相同：新增为 add，删除为 remove，修改为 replace；
+// 同一切片末尾的多个元素按下标从大到小删除，以便依次应用
+func CreatePatch(a, b *HashMap) Patch {
+	var patch Patch
+	// removals 暂存同一切片中连续删除的元素
+	var removals Patch
+	var removalParent string
+	flush := func() {
+		for i := len(removals) - 1; i >= 0; i-- {
+			patch = append(patch, removals[i])
+		}
+		removals = nil
+	}
+
+	d := newDiffer(nil)
+	d.diffMap(nil, mapNodeOf(a), mapNodeOf(b), func(t ChangeType, segments []string, old, new any) bool {
+		pointer := formatPointer(segments)
+		parent := formatPointer(segments[:len(segments)-1])
+		if len(removals) > 0 && (t != ChangeRemoved || parent != removalParent) {
+			flush()
+		}
+		switch t {
+		case ChangeAdded:
+			patch = append(patch, PatchOperation{Op: PatchAdd, Path: pointer, Value: cloneValue(new)})
+		case ChangeModified:
+			patch = append(patch, PatchOperation{Op: PatchReplace, Path: pointer, Value: cloneValue(new)})
+		case ChangeRemoved:
+			if _, err := strconv.Atoi(segments[len(segments)-1]); err == nil && isSliceValue(reflect.ValueOf(parentValue(a, segments))) {
+				removals, removalParent = append(removals, PatchOperation{Op: PatchRemove, Path: pointer}), parent
+			} else {
+				patch = append(patch, PatchOperation{Op: PatchRemove, Path: pointer})
+			}
+		}
+		return true
+	})
+	flush()
+	return patch
+}
+
+// parentValue 返回 a 中 segments 的父容器
+func parentValue(a *HashMap, segments []string) any {
+	parent := make([]pathSegment, len(segments)-1)
+	for i, token := range segments[:len(segments)-1] {
+		parent[i] = pathSegment{key: token}
+	}
+	v, _ := getPath(a, parent)
+	return v
+}
@@ -0,0 +1,176 @@
+package object
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPatch(t *testing.T) {
+	m := &HashMap{
+		"name":   "demo",
+		"port":   8080,
+		"tags":   []string{"a", "b"},
+		"db":     map[string]any{"host": "a"},
+		"a/b":    1,
+		"m~n":    2,
+		"labels": StringMap{"env": "prod"},
+	}
+	patch, err := DecodePatch([]byte(`[
+		{"op": "test", "path": "/port", "value": 8080},
+		{"op": "replace", "path": "/name", "value": "prod"},
+		{"op": "add", "path": "/tags/1", "value": "x"},
+		{"op": "add", "path": "/tags/-", "value": "z"},
+		{"op": "remove", "path": "/tags/0"},
+		{"op": "add", "path": "/db/users", "value": [{"name": "root"}]},
+		{"op": "add", "path": "/db/users/0/admin", "value": null},
+		{"op": "copy", "from": "/db/host", "path": "/db/replica"},
+		{"op": "move", "from": "/a~1b", "path": "/moved"},
+		{"op": "replace", "path": "/m~0n", "value": 3},
+		{"op": "add", "path": "/labels/team", "value": "infra"},
+		{"op": "add", "path": "/labels/owners", "value": ["ops"]}
+	]`))
+	assert.NoError(t, err)
+	assert.NoError(t, ApplyPatch(m, patch))
+
+	data, err := JsonEncode(m)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "prod",
+		"port": 8080,
+		"tags": ["x", "b", "z"],
+		"db": {"host": "a", "replica": "a", "users": [{"name": "root", "admin": null}]},
+		"moved": 1,
+		"m~n": 3,
+		"labels": {"env": "prod", "team": "infra", "owners": ["ops"]}
+	}`, data)
+	// StringMap 写入非字符串的值后转换为 HashMap，[]string 保持原有的类型
+	assert.IsType(t, HashMap{}, (*m)["labels"])
+	assert.Equal(t, []string{"x", "b", "z"}, (*m)["tags"])
+}
+
+func TestApplyPatch_Atomic(t *testing.T) {
+	m := &HashMap{"a": 1, "list": []any{1, 2}}
+	cases := []struct {
+		patch Patch
+		err   error
+		index int
+	}{
+		{Patch{{Op: PatchReplace, Path: "/a", Value: 2}, {Op: PatchTest, Path: "/a", Value: 1}}, ErrTestFailed, 1},
+		{Patch{{Op: PatchAdd, Path: "/b", Value: 1}, {Op: PatchRemove, Path: "/missing"}}, ErrNotFound, 1},
+		{Patch{{Op: PatchReplace, Path: "/missing", Value: 1}}, ErrNotFound, 0},
+		{Patch{{Op: PatchAdd, Path: "/list/5", Value: 1}}, ErrNotFound, 0},
+		{Patch{{Op: PatchAdd, Path: "/list/01", Value: 1}}, nil, 0},
+		{Patch{{Op: PatchAdd, Path: "/a/b", Value: 1}}, nil, 0},
+		{Patch{{Op: PatchMove, From: "/list", Path: "/list/0"}}, nil, 0},
+		{Patch{{Op: PatchRemove, Path: ""}}, nil, 0},
+		{Patch{{Op: PatchAdd, Path: "a"}}, nil, 0},
+		{Patch{{Op: PatchAdd, Path: "/~2"}}, nil, 0},
+		{Patch{{Op: "merge", Path: "/a"}}, nil, 0},
+	}
+	for _, c := range cases {
+		err := ApplyPatch(m, c.patch)
+		var patchErr *PatchError
+		if assert.True(t, errors.As(err, &patchErr), "%v", c.patch) {
+			assert.Equal(t, c.index, patchErr.Index)
+		}
+		if c.err != nil {
+			assert.True(t, errors.Is(err, c.err), "%v", err)
+		}
+		assert.Equal(t, &HashMap{"a": 1, "list": []any{1, 2}}, m)
+	}
+}
+
+func TestApplyPatch_Root(t *testing.T) {
+	c := NewCollection(&HashMap{"a": 1})
+	assert.NoError(t, c.ApplyPatch(Patch{
+		{Op: PatchTest, Path: "", Value: map[string]any{"a": 1.0}},
+		{Op: PatchReplace, Path: "", Value: map[string]any{"b": 2}},
+	}))
+	assert.Equal(t, &HashMap{"b": 2}, c.All())
+	assert.Error(t, c.ApplyPatch(Patch{{Op: PatchAdd, Path: "", Value: 1}}))
+
+	s := NewSafeCollection(&HashMap{"n": 1})
+	assert.NoError(t, s.ApplyPatch(Patch{{Op: PatchReplace, Path: "/n", Value: 2}}))
+	assert.Equal(t, 2, s.Get("n"))
+}
+
+func TestDecodePatch(t *testing.T) {
+	_, err := DecodePatch([]byte(`[{"op": "add", "path": "/a"}]`))
+	assert.ErrorContains(t, err, `missing "value"`)
+	_, err = DecodePatch([]byte(`[{"op": "move", "path": "/a"}]`))
+	assert.ErrorContains(t, err, `missing "from"`)
+	_, err = DecodePatch([]byte(`[{"op": "remove"}]`))
+	assert.ErrorContains(t, err, `missing "path"`)
+	_, err = DecodePatch([]byte(`[{"op": "merge", "path": "/a"}]`))
+	assert.ErrorContains(t, err, `unknown patch operation "merge"`)
+
+	patch := Patch{
+		{Op: PatchAdd, Path: "/a~1b", Value: nil},
+		{Op: PatchRemove, Path: "/c"},
+		{Op: PatchMove, From: "/d", Path: "/e"},
+	}
+	data, err := JsonEncode(patch)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "add", "path": "/a~1b", "value": null},
+		{"op": "remove", "path": "/c"},
+		{"op": "move", "from": "/d", "path": "/e"}
+	]`, data)
+
+	decoded, err := DecodePatch([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, patch, decoded)
+}
+
+func TestPointer(t *testing.T) {
+	for pointer, tokens := range map[string][]string{
+		"":        nil,
+		"/":       {""},
+		"/a~1b/0": {"a/b", "0"},
+		"/m~0n":   {"m~n"},
+		"/~01":    {"~1"},
+	} {
+		got, err := parsePointer(pointer)
+		assert.NoError(t, err)
+		assert.Equal(t, tokens, got)
+		assert.Equal(t, pointer, formatPointer(got))
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	a := &HashMap{
+		"name":  "demo",
+		"tags":  []any{"a", "b", "c", "d"},
+		"db":    map[string]any{"host": "a", "old": true},
+		"a/b":   1,
+		"codes": map[string]any{"0": "x", "1": "y"},
+	}
+	b := &HashMap{
+		"name":  "prod",
+		"tags":  []any{"x"},
+		"db":    map[string]any{"host": "a", "users": []any{"root"}},
+		"m~n":   2,
+		"codes": map[string]any{},
+	}
+
+	patch := CreatePatch(a, b)
+	assert.Equal(t, Patch{
+		{Op: PatchRemove, Path: "/a~1b"},
+		{Op: PatchRemove, Path: "/codes/0"},
+		{Op: PatchRemove, Path: "/codes/1"},
+		{Op: PatchRemove, Path: "/db/old"},
+		{Op: PatchAdd, Path: "/db/users", Value: []any{"root"}},
+		{Op: PatchAdd, Path: "/m~0n", Value: 2},
+		{Op: PatchReplace, Path: "/name", Value: "prod"},
+		{Op: PatchReplace, Path: "/tags/0", Value: "x"},
+		{Op: PatchRemove, Path: "/tags/3"},
+		{Op: PatchRemove, Path: "/tags/2"},
+		{Op: PatchRemove, Path: "/tags/1"},
+	}, patch)
+
+	assert.NoError(t, ApplyPatch(a, patch))
+	assert.Empty(t, Diff(a, b, nil))
+	assert.Empty(t, CreatePatch(a, b))
+}
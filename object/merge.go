@@ -0,0 +1,142 @@
+package object
+
+import (
+	"reflect"
+)
+
+// MergeStrategy 决定深度合并时，目标和来源都有同一个键（且不都是 Map）时如何取值
+type MergeStrategy int
+
+const (
+	// MergeKeepTarget 保留目标的值，只补充目标中不存在的键
+	MergeKeepTarget MergeStrategy = iota
+	// MergeOverwrite 使用来源的值覆盖目标的值
+	MergeOverwrite
+	// MergeFillZero 只在目标为零值且来源不为零值时使用来源的值，与 MergeMap 相同
+	MergeFillZero
+	// MergeAppendSlices 两边都是切片时将来源追加到目标之后，其他值使用来源的值覆盖
+	MergeAppendSlices
+)
+
+// MergeResolver 是自定义的合并函数，path 为"点"表示法的键路径，返回值写入目标
+type MergeResolver func(path string, target, source any) any
+
+// DeepMergeMap 按 strategy 将 sources 依次深度合并到 target。
+// 值为 Map（HashMap、map[string]any、StringMap 等）的键会递归合并，而不是整体替换；
+// 目标中不存在的键总是使用来源的值（其中的 Map 会被复制，不与来源共享）。
+func DeepMergeMap[V any](target *Map[V], strategy MergeStrategy, sources ...*Map[V]) *Map[V] {
+	return deepMerge(target, strategyResolver(strategy), sources)
+}
+
+// DeepMergeMapFunc 与 DeepMergeMap 相同，但冲突的值由 resolve 决定
+func DeepMergeMapFunc[V any](target *Map[V], resolve MergeResolver, sources ...*Map[V]) *Map[V] {
+	return deepMerge(target, resolve, sources)
+}
+
+func deepMerge[V any](target *Map[V], resolve MergeResolver, sources []*Map[V]) *Map[V] {
+	if target == nil {
+		target = &Map[V]{}
+	}
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+		for k, v := range *source {
+			existing, exists := (*target)[k]
+			if !exists {
+				if c, ok := cloneMaps(any(v)).(V); ok {
+					v = c
+				}
+				(*target)[k] = v
+				continue
+			}
+			merged := mergeValue(k, existing, v, resolve)
+			if merged == nil {
+				var zero V
+				(*target)[k] = zero
+			} else if mv, ok := merged.(V); ok {
+				(*target)[k] = mv
+			}
+		}
+	}
+	return target
+}
+
+// mergeValue 合并同一路径上的两个值，两边都是 Map 时递归合并到目标中
+func mergeValue(path string, target, source any, resolve MergeResolver) any {
+	dst, dstIsMap := asMapNode(target)
+	src, srcIsMap := asMapNode(source)
+	if !dstIsMap || !srcIsMap || dst.isNil() {
+		return resolve(path, target, source)
+	}
+
+	for _, k := range src.keys() {
+		v, _ := src.get(k)
+		existing, exists := dst.get(k)
+		if !exists {
+			v = cloneMaps(v)
+		} else {
+			v = mergeValue(path+"."+k, existing, v, resolve)
+		}
+		// 值类型无法容纳合并结果时 target 被替换为 HashMap
+		target = setMapKey(target, dst, k, v)
+		dst, _ = asMapNode(target)
+	}
+	return target
+}
+
+func strategyResolver(strategy MergeStrategy) MergeResolver {
+	switch strategy {
+	case MergeOverwrite:
+		return func(_ string, _, source any) any {
+			return cloneMaps(source)
+		}
+	case MergeFillZero:
+		return func(_ string, target, source any) any {
+			if IsZero(target) && !IsZero(source) {
+				return cloneMaps(source)
+			}
+			return target
+		}
+	case MergeAppendSlices:
+		return func(_ string, target, source any) any {
+			if merged, ok := appendSlices(target, source); ok {
+				return merged
+			}
+			return cloneMaps(source)
+		}
+	default:
+		return func(_ string, target, _ any) any {
+			return target
+		}
+	}
+}
+
+// cloneMaps 深度复制 v 中的 Map，保持原有的类型，其他值（包括切片）原样返回
+func cloneMaps(v any) any {
+	return cloneWith(v, false)
+}
+
+// appendSlices 返回 target 和 source 拼接后的新切片。
+// 类型相同时保持原类型，否则两边都是切片时返回 []any。
+func appendSlices(target, source any) (any, bool) {
+	tv, sv := reflect.ValueOf(target), reflect.ValueOf(source)
+	if !tv.IsValid() || !sv.IsValid() || tv.Kind() != reflect.Slice || sv.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	if tv.Type() == sv.Type() {
+		merged := reflect.MakeSlice(tv.Type(), 0, tv.Len()+sv.Len())
+		merged = reflect.AppendSlice(merged, tv)
+		merged = reflect.AppendSlice(merged, sv)
+		return merged.Interface(), true
+	}
+
+	merged := make([]any, 0, tv.Len()+sv.Len())
+	for _, s := range []reflect.Value{tv, sv} {
+		for i := 0; i < s.Len(); i++ {
+			merged = append(merged, s.Index(i).Interface())
+		}
+	}
+	return merged, true
+}
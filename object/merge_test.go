@@ -0,0 +1,157 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newLayers 返回默认配置和覆盖配置，嵌套值混用三种 Map 类型
+func newLayers() (*HashMap, *HashMap) {
+	defaults := &HashMap{
+		"name": "app",
+		"port": 0,
+		"tags": []string{"base"},
+		"db": &HashMap{
+			"host": "localhost",
+			"port": 3306,
+			"pool": map[string]any{"max": 10, "min": 1},
+		},
+		"log": HashMap{"level": "info"},
+	}
+	overrides := &HashMap{
+		"name": "prod",
+		"port": 8080,
+		"tags": []string{"prod"},
+		"db": map[string]any{
+			"host": "db.internal",
+			"pool": &HashMap{"max": 50},
+			"user": "root",
+		},
+		"log":   HashMap{"level": "", "file": "/var/log/app.log"},
+		"extra": &HashMap{"enabled": true},
+	}
+	return defaults, overrides
+}
+
+func TestDeepMergeMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		expected *HashMap
+	}{
+		{
+			name:     "保留目标",
+			strategy: MergeKeepTarget,
+			expected: &HashMap{
+				"name": "app",
+				"port": 0,
+				"tags": []string{"base"},
+				"db": &HashMap{
+					"host": "localhost",
+					"port": 3306,
+					"pool": map[string]any{"max": 10, "min": 1},
+					"user": "root",
+				},
+				"log":   HashMap{"level": "info", "file": "/var/log/app.log"},
+				"extra": &HashMap{"enabled": true},
+			},
+		},
+		{
+			name:     "覆盖",
+			strategy: MergeOverwrite,
+			expected: &HashMap{
+				"name": "prod",
+				"port": 8080,
+				"tags": []string{"prod"},
+				"db": &HashMap{
+					"host": "db.internal",
+					"port": 3306,
+					"pool": map[string]any{"max": 50, "min": 1},
+					"user": "root",
+				},
+				"log":   HashMap{"level": "", "file": "/var/log/app.log"},
+				"extra": &HashMap{"enabled": true},
+			},
+		},
+		{
+			name:     "填充零值",
+			strategy: MergeFillZero,
+			expected: &HashMap{
+				"name": "app",
+				"port": 8080,
+				"tags": []string{"base"},
+				"db": &HashMap{
+					"host": "localhost",
+					"port": 3306,
+					"pool": map[string]any{"max": 10, "min": 1},
+					"user": "root",
+				},
+				"log":   HashMap{"level": "info", "file": "/var/log/app.log"},
+				"extra": &HashMap{"enabled": true},
+			},
+		},
+		{
+			name:     "追加切片",
+			strategy: MergeAppendSlices,
+			expected: &HashMap{
+				"name": "prod",
+				"port": 8080,
+				"tags": []string{"base", "prod"},
+				"db": &HashMap{
+					"host": "db.internal",
+					"port": 3306,
+					"pool": map[string]any{"max": 50, "min": 1},
+					"user": "root",
+				},
+				"log":   HashMap{"level": "", "file": "/var/log/app.log"},
+				"extra": &HashMap{"enabled": true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, source := newLayers()
+			result := DeepMergeMap(target, tt.strategy, source)
+			assert.Same(t, target, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestDeepMergeMap_DoesNotShareSource(t *testing.T) {
+	source := &HashMap{"db": &HashMap{"host": "a"}}
+	target := DeepMergeMap(nil, MergeOverwrite, source)
+	DeepMergeMap(target, MergeOverwrite, &HashMap{"db": &HashMap{"host": "b"}})
+
+	assert.Equal(t, "b", (*(*target)["db"].(*HashMap))["host"])
+	assert.Equal(t, "a", (*(*source)["db"].(*HashMap))["host"])
+}
+
+func TestDeepMergeMap_AppendMixedSlices(t *testing.T) {
+	target := &HashMap{"ids": []int{1}, "names": []any{"a"}}
+	DeepMergeMap(target, MergeAppendSlices, &HashMap{"ids": []any{"2"}, "names": []any{"b"}}, nil)
+	assert.Equal(t, &HashMap{"ids": []any{1, "2"}, "names": []any{"a", "b"}}, target)
+}
+
+func TestDeepMergeMap_StringMap(t *testing.T) {
+	target := &StringMap{"a": "1", "b": ""}
+	DeepMergeMap(target, MergeFillZero, &StringMap{"b": "2", "c": "3"})
+	assert.Equal(t, &StringMap{"a": "1", "b": "2", "c": "3"}, target)
+}
+
+func TestDeepMergeMapFunc(t *testing.T) {
+	target, source := newLayers()
+	var paths []string
+	DeepMergeMapFunc(target, func(path string, dst, src any) any {
+		paths = append(paths, path)
+		if path == "db.pool.max" {
+			return dst.(int) + src.(int)
+		}
+		return dst
+	}, source)
+
+	assert.ElementsMatch(t, []string{"name", "port", "tags", "db.host", "db.pool.max", "log.level"}, paths)
+	assert.Equal(t, 60, NewCollection(target).Get("db.pool").(map[string]any)["max"])
+}
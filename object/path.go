@@ -0,0 +1,410 @@
+package object
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pathSegment 是"点"表示法路径中的一段
+type pathSegment struct {
+	key string
+	// bracket 表示该段以 [n] 的形式给出，写入时在不存在的位置创建切片而不是 Map
+	bracket bool
+}
+
+// parsePath 解析 "items.0.sku"、"items[0].sku"、"matrix[0][-1]" 和 `a["x.y"]` 形式的路径，
+// 引号中的键按 Go 字符串字面量解析，可以包含 "."、"[" 和 "]"
+func parsePath(path string) []pathSegment {
+	return parsePathSep(path, ".")
+}
+
+// parsePathSep 与 parsePath 相同，但使用 sep 分隔各段
+func parsePathSep(path, sep string) []pathSegment {
+	var segments []pathSegment
+	for _, part := range splitPath(path, sep) {
+		for {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				segments = append(segments, pathSegment{key: part})
+				break
+			}
+			var seg pathSegment
+			var n int
+			if key, size, ok := quotedSegment(part[open:]); ok {
+				seg, n = pathSegment{key: key}, size
+			} else if end := strings.IndexByte(part[open:], ']'); end >= 0 {
+				seg, n = pathSegment{key: part[open+1 : open+end], bracket: true}, end+1
+			} else {
+				segments = append(segments, pathSegment{key: part})
+				break
+			}
+			if open > 0 {
+				segments = append(segments, pathSegment{key: part[:open]})
+			}
+			segments = append(segments, seg)
+			part = part[open+n:]
+			if part == "" {
+				break
+			}
+		}
+	}
+	return segments
+}
+
+// splitPath 按 sep 切分路径，["..."] 中的 sep 不作为分隔符
+func splitPath(path, sep string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(path); {
+		if _, n, ok := quotedSegment(path[i:]); ok {
+			i += n
+			continue
+		}
+		if strings.HasPrefix(path[i:], sep) {
+			parts = append(parts, path[start:i])
+			i += len(sep)
+			start = i
+			continue
+		}
+		i++
+	}
+	return append(parts, path[start:])
+}
+
+// quotedSegment 解析 s 开头的 ["..."]，返回其中的键和所占的字节数
+func quotedSegment(s string) (string, int, bool) {
+	if !strings.HasPrefix(s, `["`) {
+		return "", 0, false
+	}
+	q, err := strconv.QuotedPrefix(s[1:])
+	if err != nil || !strings.HasPrefix(s[1+len(q):], "]") {
+		return "", 0, false
+	}
+	key, err := strconv.Unquote(q)
+	if err != nil {
+		return "", 0, false
+	}
+	return key, len(q) + 2, true
+}
+
+// joinPath 将键追加到路径后，包含 "."、"[" 或 "]" 的键写作 ["a.b"]，以便区分
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sliceIndex 将段解析为长度为 n 的切片的下标，负数从末尾开始计算
+func sliceIndex(key string, n int) (int, bool) {
+	i, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, false
+	}
+	if i < 0 {
+		i += n
+		if i < 0 {
+			return 0, false
+		}
+	}
+	return i, true
+}
+
+// mapNode 统一访问路径上的 Map：HashMap、*HashMap、map[string]any 直接访问，
+// StringMap、其他 Map[V] 以及键为 string 的 map（和指向它们的指针）通过反射访问
+type mapNode struct {
+	m  map[string]any
+	rv reflect.Value
+}
+
+// asMapNode 判断 v 是否为路径可以进入的 Map，返回的 mapNode 的修改会反映到 v 上
+func asMapNode(v any) (mapNode, bool) {
+	switch m := v.(type) {
+	case HashMap:
+		return mapNode{m: m}, true
+	case *HashMap:
+		if m == nil {
+			return mapNode{}, true
+		}
+		return mapNode{m: *m}, true
+	case map[string]any:
+		return mapNode{m: m}, true
+	case nil:
+		return mapNode{}, false
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.Type().Elem().Kind() != reflect.Map {
+			return mapNode{}, false
+		}
+		if rv.IsNil() {
+			return mapNode{rv: reflect.Zero(rv.Type().Elem())}, rv.Type().Elem().Key().Kind() == reflect.String
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return mapNode{}, false
+	}
+	return mapNode{rv: rv}, true
+}
+
+// isNil 判断 Map 是否为 nil，nil 的 Map 只能读取
+func (n mapNode) isNil() bool {
+	if n.rv.IsValid() {
+		return n.rv.IsNil()
+	}
+	return n.m == nil
+}
+
+func (n mapNode) len() int {
+	if n.rv.IsValid() {
+		return n.rv.Len()
+	}
+	return len(n.m)
+}
+
+func (n mapNode) get(key string) (any, bool) {
+	if !n.rv.IsValid() {
+		v, ok := n.m[key]
+		return v, ok
+	}
+	v := n.rv.MapIndex(reflect.ValueOf(key).Convert(n.rv.Type().Key()))
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// set 写入 key，值的类型与 Map 的值类型不符时返回 false
+func (n mapNode) set(key string, value any) bool {
+	if !n.rv.IsValid() {
+		n.m[key] = value
+		return true
+	}
+	elemType := n.rv.Type().Elem()
+	ev := reflect.ValueOf(value)
+	if !ev.IsValid() {
+		ev = reflect.Zero(elemType)
+	} else if !ev.Type().AssignableTo(elemType) {
+		return false
+	}
+	n.rv.SetMapIndex(reflect.ValueOf(key).Convert(n.rv.Type().Key()), ev)
+	return true
+}
+
+func (n mapNode) delete(key string) {
+	if !n.rv.IsValid() {
+		delete(n.m, key)
+		return
+	}
+	n.rv.SetMapIndex(reflect.ValueOf(key).Convert(n.rv.Type().Key()), reflect.Value{})
+}
+
+// keys 返回按字典序排列的键
+func (n mapNode) keys() []string {
+	keys := make([]string, 0, n.len())
+	if !n.rv.IsValid() {
+		for k := range n.m {
+			keys = append(keys, k)
+		}
+	} else {
+		for _, k := range n.rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// toHashMap 返回 Map 内容的浅复制
+func (n mapNode) toHashMap() HashMap {
+	m := make(HashMap, n.len())
+	for _, k := range n.keys() {
+		m[k], _ = n.get(k)
+	}
+	return m
+}
+
+// newMapLike 为 nil 的 Map 创建同类型的空 Map，返回新的容器
+func newMapLike(current any) (any, mapNode) {
+	switch current.(type) {
+	case HashMap:
+		m := HashMap{}
+		return m, mapNode{m: m}
+	case *HashMap:
+		m := HashMap{}
+		return &m, mapNode{m: m}
+	case map[string]any:
+		m := map[string]any{}
+		return m, mapNode{m: m}
+	}
+	rv := reflect.ValueOf(current)
+	if rv.Kind() == reflect.Pointer {
+		ptr := reflect.New(rv.Type().Elem())
+		ptr.Elem().Set(reflect.MakeMap(rv.Type().Elem()))
+		return ptr.Interface(), mapNode{rv: ptr.Elem()}
+	}
+	m := reflect.MakeMap(rv.Type())
+	return m.Interface(), mapNode{rv: m}
+}
+
+// setMapKey 将 value 写入 Map current 的 key，返回写入后的容器。
+// nil 的 Map 替换为同类型的空 Map；值类型无法容纳 value 时（如向 StringMap 写入 Map），
+// 内容被复制到新的 HashMap（指针为 *HashMap）中再写入
+func setMapKey(current any, n mapNode, key string, value any) any {
+	if n.isNil() {
+		current, n = newMapLike(current)
+	}
+	if n.set(key, value) {
+		return current
+	}
+	m := n.toHashMap()
+	m[key] = value
+	if reflect.ValueOf(current).Kind() == reflect.Pointer {
+		return &m
+	}
+	return m
+}
+
+// getPath 按路径读取 current 中的值，可以穿过 Map 和任意切片
+func getPath(current any, segments []pathSegment) (any, bool) {
+	for _, seg := range segments {
+		if n, ok := asMapNode(current); ok {
+			if n.isNil() {
+				return nil, false
+			}
+			val, exists := n.get(seg.key)
+			if !exists {
+				return nil, false
+			}
+			current = val
+			continue
+		}
+
+		rv := reflect.ValueOf(current)
+		if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+			return nil, false
+		}
+		i, ok := sliceIndex(seg.key, rv.Len())
+		if !ok || i >= rv.Len() {
+			return nil, false
+		}
+		current = rv.Index(i).Interface()
+	}
+	return current, true
+}
+
+// maxSliceGrowth 是写入时一次最多在切片末尾之后扩展的元素个数，
+// 避免 "items[99999999999]" 这样的路径分配巨大的切片
+const maxSliceGrowth = 1024
+
+// skippedWrite 是 setPath 拒绝写入时返回的标记，调用方应保持原值不变
+type skippedWrite struct{}
+
+// newNode 创建路径上缺少的 Map，其中只有 key 一个键
+type newNode func(key string, child any) any
+
+func newHashMapNode(key string, child any) any {
+	return &HashMap{key: child}
+}
+
+func newStringAnyMapNode(key string, child any) any {
+	return map[string]any{key: child}
+}
+
+// setPath 按路径将 value 写入 current，返回写入后的容器。
+// Map 原地修改（见 setMapKey）；切片长度不足时追加零值，因此可能返回新的切片，数组被复制为切片；
+// 路径上的其他值被替换为新的 *HashMap（[n] 形式的段为 []any）。
+// 下标超出末尾 maxSliceGrowth 个以上或为超出范围的负数时不写入，返回 skippedWrite。
+func setPath(current any, segments []pathSegment, value any) any {
+	return setPathWith(current, segments, value, newHashMapNode)
+}
+
+// setPathWith 与 setPath 相同，但由 node 创建路径上缺少的 Map
+func setPathWith(current any, segments []pathSegment, value any, node newNode) any {
+	if len(segments) == 0 {
+		return value
+	}
+	seg, rest := segments[0], segments[1:]
+
+	if n, ok := asMapNode(current); ok {
+		var child any
+		if !n.isNil() {
+			child, _ = n.get(seg.key)
+		}
+		updated := setPathWith(child, rest, value, node)
+		if _, skip := updated.(skippedWrite); skip {
+			return updated
+		}
+		return setMapKey(current, n, seg.key, updated)
+	}
+
+	rv := reflect.ValueOf(current)
+	if rv.IsValid() && rv.Kind() == reflect.Array {
+		s := reflect.MakeSlice(reflect.SliceOf(rv.Type().Elem()), rv.Len(), rv.Len())
+		reflect.Copy(s, rv)
+		rv = s
+	}
+	if rv.IsValid() && rv.Kind() == reflect.Slice {
+		i, ok := sliceIndex(seg.key, rv.Len())
+		if !ok {
+			if _, err := strconv.Atoi(seg.key); err == nil {
+				// 超出范围的负数下标
+				return skippedWrite{}
+			}
+			return setPathWith(nil, segments, value, node)
+		}
+		return setSliceIndex(rv, i, rest, value, node)
+	}
+
+	if seg.bracket {
+		if i, err := strconv.Atoi(seg.key); err == nil && i >= 0 {
+			return setSliceIndex(reflect.ValueOf([]any{}), i, rest, value, node)
+		}
+	}
+	child := setPathWith(nil, rest, value, node)
+	if _, skip := child.(skippedWrite); skip {
+		return child
+	}
+	return node(seg.key, child)
+}
+
+// setSliceIndex 写入切片 rv 的第 i 个元素，必要时扩展切片；
+// 元素类型无法容纳新值时将切片转换为 []any
+func setSliceIndex(rv reflect.Value, i int, rest []pathSegment, value any, node newNode) any {
+	if i-rv.Len() >= maxSliceGrowth {
+		return skippedWrite{}
+	}
+	if i >= rv.Len() {
+		grown := reflect.MakeSlice(rv.Type(), i+1, i+1)
+		reflect.Copy(grown, rv)
+		rv = grown
+	}
+
+	elem := setPathWith(rv.Index(i).Interface(), rest, value, node)
+	if _, skip := elem.(skippedWrite); skip {
+		return elem
+	}
+	ev := reflect.ValueOf(elem)
+	elemType := rv.Type().Elem()
+	switch {
+	case !ev.IsValid():
+		rv.Index(i).Set(reflect.Zero(elemType))
+	case ev.Type().AssignableTo(elemType):
+		rv.Index(i).Set(ev)
+	default:
+		items := make([]any, rv.Len())
+		for j := range items {
+			items[j] = rv.Index(j).Interface()
+		}
+		items[i] = elem
+		return items
+	}
+	return rv.Interface()
+}
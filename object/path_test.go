@@ -0,0 +1,85 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath_MixedMapTypes(t *testing.T) {
+	var decoded HashMap
+	assert.NoError(t, JsonDecode([]byte(`{"db":{"hosts":[{"name":"a"}]}}`), &decoded))
+
+	items := &HashMap{
+		"json":   map[string]any(decoded),
+		"hash":   HashMap{"inner": &HashMap{"v": 1}},
+		"labels": StringMap{"env": "prod"},
+		"ptr":    &StringMap{"zone": "cn"},
+		"ints":   Map[int]{"max": 10},
+	}
+	c := NewCollection(items)
+
+	assert.Equal(t, "a", c.Get("json.db.hosts.0.name"))
+	assert.Equal(t, 1, c.Get("hash.inner.v"))
+	assert.Equal(t, "prod", c.Get("labels.env"))
+	assert.Equal(t, "cn", c.Get("ptr.zone"))
+	assert.Equal(t, 10, c.Get("ints.max"))
+	assert.True(t, c.Has("labels.env"))
+	assert.False(t, c.Has("labels.missing"))
+	assert.Equal(t, "prod", c.GetString("labels.env"))
+	assert.Equal(t, "cn", MustGetAs[string](StringMap{"zone": "cn"}, "zone"))
+
+	// 值类型相符时原地写入
+	c.Set("labels.team", "infra")
+	assert.Equal(t, StringMap{"env": "prod", "team": "infra"}, (*items)["labels"])
+	c.Set("ints.min", 1)
+	assert.Equal(t, Map[int]{"max": 10, "min": 1}, (*items)["ints"])
+
+	// 值类型不符时转换为 HashMap
+	c.Set("labels.owner.name", "ops")
+	assert.Equal(t, HashMap{"env": "prod", "team": "infra", "owner": &HashMap{"name": "ops"}}, (*items)["labels"])
+	c.Set("ptr.count", 2)
+	assert.Equal(t, &HashMap{"zone": "cn", "count": 2}, (*items)["ptr"])
+
+	c.Delete("ints.max", "json.db.hosts.0.name")
+	assert.Equal(t, Map[int]{"min": 1}, (*items)["ints"])
+	assert.True(t, c.Has("json.db.hosts.0"))
+	assert.False(t, c.Has("json.db.hosts.0.name"))
+
+	var paths []string
+	for path := range c.Walk() {
+		paths = append(paths, path)
+	}
+	assert.Contains(t, paths, "hash.inner.v")
+	assert.Contains(t, paths, "ints.min")
+}
+
+func TestPath_CloneTypedMaps(t *testing.T) {
+	src := map[string]any{
+		"labels": StringMap{"env": "prod"},
+		"ptr":    &StringMap{"zone": "cn"},
+		"nested": map[string]HashMap{"a": {"b": 1}},
+	}
+	dst := cloneValue(src).(map[string]any)
+	assert.Equal(t, src, dst)
+
+	dst["labels"].(StringMap)["env"] = "dev"
+	(*dst["ptr"].(*StringMap))["zone"] = "us"
+	dst["nested"].(map[string]HashMap)["a"]["b"] = 2
+	assert.Equal(t, "prod", src["labels"].(StringMap)["env"])
+	assert.Equal(t, "cn", (*src["ptr"].(*StringMap))["zone"])
+	assert.Equal(t, 1, src["nested"].(map[string]HashMap)["a"]["b"])
+}
+
+func TestDeepMergeMap_TypedMaps(t *testing.T) {
+	target := &HashMap{"labels": StringMap{"env": "prod"}}
+	DeepMergeMap(target, MergeOverwrite,
+		&HashMap{"labels": map[string]any{"team": "infra"}},
+		&HashMap{"labels": HashMap{"owner": HashMap{"name": "ops"}}},
+	)
+	assert.Equal(t, &HashMap{"labels": HashMap{
+		"env":   "prod",
+		"team":  "infra",
+		"owner": HashMap{"name": "ops"},
+	}}, target)
+}
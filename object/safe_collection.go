@@ -0,0 +1,117 @@
+package object
+
+import (
+	"reflect"
+	"sync"
+)
+
+// SafeCollection 是并发安全的集合，可以在多个 goroutine 之间共享。
+// 路径语法与 Collection 相同。Get 返回、Set 保存的 Map 和切片都是副本，
+// 集合外的修改不会影响集合，需要原地修改时使用 Update。
+type SafeCollection struct {
+	mu sync.RWMutex
+	c  *Collection
+}
+
+// NewSafeCollection 创建并发安全的集合，items 此后只应通过 SafeCollection 访问
+func NewSafeCollection(items *HashMap) *SafeCollection {
+	return &SafeCollection{c: NewCollection(items)}
+}
+
+// Get 使用"点"表示法从集合中获取项目，见 Collection.Get
+func (s *SafeCollection) Get(key string, defaultValue ...any) any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return cloneValue(s.c.Get(key, defaultValue...))
+}
+
+// Set 设置集合中的值，返回是否写入，见 Collection.Set。保存的是 value 中 Map 和切片的副本
+func (s *SafeCollection) Set(key string, value any) bool {
+	value = cloneValue(value)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.Set(key, value)
+}
+
+// Has 检查键是否存在
+func (s *SafeCollection) Has(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Has(key)
+}
+
+// Count 返回集合中的项目数
+func (s *SafeCollection) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Count()
+}
+
+// Delete 删除集合中的项目，见 Collection.Delete
+func (s *SafeCollection) Delete(paths ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.Delete(paths...)
+}
+
+// Pull 获取并删除路径上的项目，见 Collection.Pull
+func (s *SafeCollection) Pull(path string, defaultValue ...any) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.Pull(path, defaultValue...)
+}
+
+// Update 在写锁内调用 fn，fn 中对 c 的多次修改对其他 goroutine 是原子的。
+// c 只在 fn 执行期间有效，不能在 fn 返回后继续使用
+func (s *SafeCollection) Update(fn func(c *Collection)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.c)
+}
+
+// View 在读锁内调用 fn，fn 不能修改 c，也不能在返回后继续使用 c
+func (s *SafeCollection) View(fn func(c *Collection)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.c)
+}
+
+// CompareAndSet 在路径上的值与 old 相等（reflect.DeepEqual）时设置为 value，返回是否设置成功。
+// old 为 nil 表示路径不存在或值为 nil
+func (s *SafeCollection) CompareAndSet(key string, old, value any) bool {
+	if key == "" {
+		return false
+	}
+	value = cloneValue(value)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := parsePath(key)
+	current, ok := getPath(s.c.items, segments)
+	if !ok {
+		current = nil
+	}
+	if !reflect.DeepEqual(current, old) {
+		return false
+	}
+	return s.c.setSegments(segments, value)
+}
+
+// Snapshot 返回集合当前内容的深度副本
+func (s *SafeCollection) Snapshot() *Collection {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return NewCollection(cloneValue(s.c.items).(*HashMap))
+}
+
+// ToJson 返回集合在某一时刻的一致的 JSON 表示
+func (s *SafeCollection) ToJson() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.ToJson()
+}
+
+func (s *SafeCollection) String() string {
+	strJson, _ := s.ToJson()
+	return strJson
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeCollection(t *testing.T) {
+	s := NewSafeCollection(&HashMap{"db": &HashMap{"host": "localhost"}})
+
+	s.Set("db.port", 3306)
+	assert.Equal(t, 3306, s.Get("db.port"))
+	assert.True(t, s.Has("db"))
+	assert.Equal(t, 1, s.Count())
+
+	// Get 返回副本
+	db := s.Get("db").(*HashMap)
+	(*db)["host"] = "changed"
+	assert.Equal(t, "localhost", s.Get("db.host"))
+
+	assert.Equal(t, 3306, s.Pull("db.port"))
+	s.Delete("db.host")
+	assert.Equal(t, `{"db":{}}`, s.String())
+
+	snapshot := s.Snapshot()
+	s.Set("db.host", "new")
+	assert.Equal(t, "none", snapshot.Get("db.host", "none"))
+}
+
+func TestSafeCollection_CompareAndSet(t *testing.T) {
+	s := NewSafeCollection(nil)
+	assert.True(t, s.CompareAndSet("leader", nil, "a"))
+	assert.False(t, s.CompareAndSet("leader", nil, "b"))
+	assert.True(t, s.CompareAndSet("leader", "a", "b"))
+	assert.Equal(t, "b", s.Get("leader"))
+
+	s.Set("tags", []string{"x"})
+	assert.True(t, s.CompareAndSet("tags", []string{"x"}, []string{"x", "y"}))
+	assert.False(t, s.CompareAndSet("", nil, 1))
+}
+
+func TestSafeCollection_Concurrent(t *testing.T) {
+	s := NewSafeCollection(nil)
+	s.Set("counter", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s.Update(func(c *Collection) {
+					c.Set("counter", c.Get("counter", 0).(int)+1)
+					c.Set(fmt.Sprintf("workers.w%d", i), j)
+				})
+				_ = s.Get("workers")
+				_, _ = s.ToJson()
+				for {
+					old := s.Get("cas", 0)
+					if old == 0 {
+						old = nil
+					}
+					next := 1
+					if old != nil {
+						next = old.(int) + 1
+					}
+					if s.CompareAndSet("cas", old, next) {
+						break
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1000, s.Get("counter"))
+	assert.Equal(t, 1000, s.Get("cas"))
+	s.View(func(c *Collection) {
+		assert.Equal(t, 20, len(*c.Get("workers").(*HashMap)))
+	})
+}
@@ -0,0 +1,195 @@
+package object
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot 是不可变的配置快照，可以被任意多个 goroutine 无锁读取。
+// 创建时会复制数据并预先计算全部"点"表示法路径（如 "items.0.sku"）的索引，
+// 读取这些路径只需一次 map 查找；"[n]" 和负数下标等其他写法按路径逐级查找。
+// Get 返回的 Map 和切片与快照共享，调用方不能修改它们。
+type Snapshot struct {
+	version uint64
+	data    *HashMap
+	index   map[string]any
+}
+
+// NewSnapshot 从 items 创建版本为 0 的快照，items 中的 Map 和切片会被复制
+func NewSnapshot(items *HashMap) *Snapshot {
+	return newSnapshot(items, 0)
+}
+
+func newSnapshot(items *HashMap, version uint64) *Snapshot {
+	if items == nil {
+		items = &HashMap{}
+	}
+	data := cloneValue(items).(*HashMap)
+	s := &Snapshot{version: version, data: data, index: map[string]any{}}
+	for _, k := range slices.Sorted(maps.Keys(*data)) {
+		if indexableKey(k) {
+			s.indexValue(k, (*data)[k])
+		}
+	}
+	return s
+}
+
+// indexableKey 判断键能否出现在索引的路径中。包含 "."、"[" 或 "]" 的键
+// 按 getPath 的规则无法原样读取，不放入索引，以免与同名的嵌套路径冲突
+func indexableKey(k string) bool {
+	return !strings.ContainsAny(k, ".[]")
+}
+
+// indexValue 记录 path 以及其下全部子路径的值
+func (s *Snapshot) indexValue(path string, v any) {
+	s.index[path] = v
+	if n, ok := asMapNode(v); ok {
+		for _, k := range n.keys() {
+			if indexableKey(k) {
+				child, _ := n.get(k)
+				s.indexValue(path+"."+k, child)
+			}
+		}
+		return
+	}
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+		for i := 0; i < rv.Len(); i++ {
+			s.indexValue(path+"."+strconv.Itoa(i), rv.Index(i).Interface())
+		}
+	}
+}
+
+// Version 返回快照的版本，SnapshotStore 每次发布新快照时加一
+func (s *Snapshot) Version() uint64 {
+	return s.version
+}
+
+// Lookup 返回路径上的值以及路径是否存在，按路径找不到时把 path 当作顶层的键查找
+func (s *Snapshot) Lookup(path string) (any, bool) {
+	if v, ok := s.index[path]; ok {
+		return v, true
+	}
+	if path == "" {
+		return nil, false
+	}
+	if v, ok := getPath(s.data, parsePath(path)); ok {
+		return v, true
+	}
+	v, ok := (*s.data)[path]
+	return v, ok
+}
+
+// Get 使用"点"表示法获取值，不存在或为零值时返回 defaultValue，与 Collection.Get 相同
+func (s *Snapshot) Get(path string, defaultValue ...any) any {
+	v, ok := s.Lookup(path)
+	if !ok || IsZero(v) {
+		return GetDefaultValue(defaultValue...)
+	}
+	return v
+}
+
+// Has 检查路径是否存在
+func (s *Snapshot) Has(path string) bool {
+	_, ok := s.Lookup(path)
+	return ok
+}
+
+// Collection 返回快照内容的可修改副本
+func (s *Snapshot) Collection() *Collection {
+	return NewCollection(cloneValue(s.data).(*HashMap))
+}
+
+// ToJson 返回快照的 JSON 表示
+func (s *Snapshot) ToJson() (string, error) {
+	return JsonEncode(s.data)
+}
+
+// SnapshotSubscriber 在新快照发布后被调用，old 为之前的快照
+type SnapshotSubscriber func(old, new *Snapshot)
+
+// SnapshotStore 通过 atomic.Pointer 保存当前快照：读取是无等待的，
+// 写入时构建新的快照并整体替换，写入之间互斥。
+type SnapshotStore struct {
+	current atomic.Pointer[Snapshot]
+
+	mu          sync.Mutex
+	subscribers map[int]SnapshotSubscriber
+	nextID      int
+}
+
+// NewSnapshotStore 创建以 items 为初始内容的存储
+func NewSnapshotStore(items *HashMap) *SnapshotStore {
+	store := &SnapshotStore{subscribers: map[int]SnapshotSubscriber{}}
+	store.current.Store(NewSnapshot(items))
+	return store
+}
+
+// Load 返回当前快照。同一个快照内的多次读取是一致的
+func (s *SnapshotStore) Load() *Snapshot {
+	return s.current.Load()
+}
+
+// Get 从当前快照读取值，见 Snapshot.Get
+func (s *SnapshotStore) Get(path string, defaultValue ...any) any {
+	return s.Load().Get(path, defaultValue...)
+}
+
+// Store 发布以 items 为内容的新快照并返回它
+func (s *SnapshotStore) Store(items *HashMap) *Snapshot {
+	s.mu.Lock()
+	old, next, subscribers := s.publish(items)
+	s.mu.Unlock()
+	notify(subscribers, old, next)
+	return next
+}
+
+// Update 在当前快照的副本上调用 fn，然后发布修改后的内容。多个 Update 依次执行，不会丢失修改
+func (s *SnapshotStore) Update(fn func(c *Collection)) *Snapshot {
+	s.mu.Lock()
+	c := s.Load().Collection()
+	fn(c)
+	old, next, subscribers := s.publish(c.All())
+	s.mu.Unlock()
+	notify(subscribers, old, next)
+	return next
+}
+
+// publish 在持有 mu 时发布新快照，返回需要通知的订阅者，由调用方在释放 mu 后通知
+func (s *SnapshotStore) publish(items *HashMap) (old, next *Snapshot, subscribers []SnapshotSubscriber) {
+	old = s.Load()
+	next = newSnapshot(items, old.version+1)
+	s.current.Store(next)
+
+	for _, id := range slices.Sorted(maps.Keys(s.subscribers)) {
+		subscribers = append(subscribers, s.subscribers[id])
+	}
+	return old, next, subscribers
+}
+
+func notify(subscribers []SnapshotSubscriber, old, next *Snapshot) {
+	for _, fn := range subscribers {
+		fn(old, next)
+	}
+}
+
+// Subscribe 注册在每次发布新快照后调用的 fn，返回取消订阅的函数。
+// fn 在发布者的 goroutine 中按订阅顺序同步调用，调用时不持有锁，可以取消订阅或再次发布。
+// 多个发布者并发时通知可能乱序到达，需要时用 Version 判断快照的先后
+func (s *SnapshotStore) Subscribe(fn SnapshotSubscriber) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
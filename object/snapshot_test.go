@@ -0,0 +1,144 @@
+package object
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	items := &HashMap{
+		"server": map[string]any{"port": 8080, "hosts": []any{"a", "b"}},
+		"debug":  false,
+	}
+	s := NewSnapshot(items)
+
+	// 快照复制了数据
+	(*items)["debug"] = true
+	v, ok := s.Lookup("debug")
+	assert.True(t, ok)
+	assert.Equal(t, false, v)
+
+	assert.Equal(t, 8080, s.Get("server.port"))
+	assert.Equal(t, "b", s.Get("server.hosts.1"))
+	assert.Equal(t, "b", s.Get("server.hosts[-1]"))
+	assert.Equal(t, "none", s.Get("server.missing", "none"))
+	assert.True(t, s.Has("server.hosts.0"))
+	assert.False(t, s.Has("server.hosts.2"))
+	assert.Equal(t, 8080, MustGetAs[int](s, "server.port"))
+
+	json, err := s.ToJson()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"server":{"port":8080,"hosts":["a","b"]},"debug":false}`, json)
+
+	c := s.Collection()
+	c.Set("server.port", 9090)
+	assert.Equal(t, 8080, s.Get("server.port"))
+}
+
+func TestSnapshotStore(t *testing.T) {
+	store := NewSnapshotStore(&HashMap{"version": "v1"})
+	assert.Equal(t, uint64(0), store.Load().Version())
+
+	var mu sync.Mutex
+	var events []string
+	cancel := store.Subscribe(func(old, new *Snapshot) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, old.Get("version").(string)+"->"+new.Get("version").(string))
+	})
+
+	before := store.Load()
+	next := store.Store(&HashMap{"version": "v2", "limits": map[string]any{"rps": 100}})
+	assert.Equal(t, uint64(1), next.Version())
+	assert.Same(t, next, store.Load())
+	assert.Equal(t, "v1", before.Get("version"))
+
+	store.Update(func(c *Collection) {
+		c.Set("version", "v3")
+		c.Set("limits.rps", 200)
+	})
+	assert.Equal(t, 200, store.Get("limits.rps"))
+	assert.Equal(t, uint64(2), store.Load().Version())
+
+	cancel()
+	store.Store(&HashMap{"version": "v4"})
+	assert.Equal(t, []string{"v1->v2", "v2->v3"}, events)
+}
+
+func TestSnapshotStore_Concurrent(t *testing.T) {
+	store := NewSnapshotStore(&HashMap{"n": 0})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				store.Update(func(c *Collection) {
+					c.Set("n", c.Get("n", 0).(int)+1)
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				s := store.Load()
+				_ = s.Get("n")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1000, store.Get("n"))
+	assert.Equal(t, uint64(1000), store.Load().Version())
+}
+
+func BenchmarkSnapshotStore_Get(b *testing.B) {
+	store := NewSnapshotStore(&HashMap{
+		"server": map[string]any{"limits": map[string]any{"rps": 100}},
+	})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = store.Get("server.limits.rps")
+		}
+	})
+}
+
+func TestSnapshot_DottedKeys(t *testing.T) {
+	items := &HashMap{"a.b": 1, "a": map[string]any{"b": 2, "c.d": 3}}
+	s := NewSnapshot(items)
+	c := NewCollection(items)
+	for _, path := range []string{"a.b", "a.c.d", "a"} {
+		assert.Equal(t, c.Get(path), s.Get(path), path)
+	}
+}
+
+func TestSnapshotStore_SubscriberReentrant(t *testing.T) {
+	store := NewSnapshotStore(nil)
+
+	var cancel func()
+	calls := 0
+	cancel = store.Subscribe(func(old, new *Snapshot) {
+		calls++
+		cancel()
+		if new.Version() == 1 {
+			store.Store(&HashMap{"again": true})
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		store.Store(&HashMap{"a": 1})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Store deadlocked")
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, uint64(2), store.Load().Version())
+}